@@ -0,0 +1,150 @@
+// Command cst2py translates a CST Studio Suite macro history list
+// (Model/3D/Model.mod) into an equivalent script for another toolchain.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/emit/aedt"
+	"github.com/brtkrtz/cst2py/internal/emit/openems"
+	"github.com/brtkrtz/cst2py/internal/emit/post"
+	"github.com/brtkrtz/cst2py/internal/emit/python"
+	"github.com/brtkrtz/cst2py/internal/macro"
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/translate"
+	"github.com/brtkrtz/cst2py/internal/waveguide"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "cst2py:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("cst2py", flag.ContinueOnError)
+	emitTarget := fs.String("emit", "python", "output backend: python, openems, aedt")
+	out := fs.String("out", "", "output file for single-file backends (default: stdout)")
+	outDir := fs.String("outdir", ".", "output directory for multi-file backends (e.g. aedt)")
+	name := fs.String("name", "Model", "project name for multi-file backends (e.g. aedt)")
+	recognizeWaveguides := fs.Bool("recognize-waveguides", false,
+		"rewrite standard rectangular/circular waveguide cross-sections as Waveguide(standard=...) (python backend only)")
+	modalPorts := fs.Bool("modal-ports", false,
+		"replace discrete ports on a recognized waveguide with an analytic modal excitation (implies -recognize-waveguides)")
+	portModes := fs.String("port-mode", "",
+		"comma-separated port=mode overrides for -modal-ports, e.g. \"1=TE10,2=TE01\" (default: each port's dominant mode)")
+	refactorSweeps := fs.Bool("refactor-sweeps", false,
+		"collapse repeated per-frequency monitors into a freqs list and a for loop (python backend only)")
+	emitPost := fs.Bool("post", false,
+		"also write a companion post.py with result loaders and an HTML report() (python backend only, written to -outdir)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: cst2py [-emit=python|openems|aedt] [-out=FILE] <Model.mod>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	prog, err := macro.Parse(f)
+	if err != nil {
+		return err
+	}
+	proj, err := translate.Translate(prog)
+	if err != nil {
+		return err
+	}
+
+	if *refactorSweeps && *emitTarget != "python" {
+		return fmt.Errorf("-refactor-sweeps is only supported with -emit=python")
+	}
+	if *emitPost && *emitTarget != "python" {
+		return fmt.Errorf("-post is only supported with -emit=python")
+	}
+	if (*recognizeWaveguides || *modalPorts) && *emitTarget != "python" {
+		return fmt.Errorf("-recognize-waveguides and -modal-ports are only supported with -emit=python")
+	}
+
+	if *emitTarget == "aedt" {
+		return writeAEDT(proj, *outDir, *name)
+	}
+
+	if *recognizeWaveguides || *modalPorts {
+		for _, w := range waveguide.Recognize(proj) {
+			fmt.Fprintln(os.Stderr, "cst2py: warning:", w)
+		}
+	}
+	if *modalPorts {
+		overrides, err := parsePortModes(*portModes)
+		if err != nil {
+			return err
+		}
+		warnings, err := waveguide.Modalize(proj, overrides)
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			fmt.Fprintln(os.Stderr, "cst2py: warning:", w)
+		}
+	}
+
+	var rendered string
+	switch *emitTarget {
+	case "python":
+		rendered = python.Emit(proj, python.Options{RefactorSweeps: *refactorSweeps})
+	case "openems":
+		rendered = openems.Emit(proj)
+	default:
+		return fmt.Errorf("unknown -emit target %q", *emitTarget)
+	}
+
+	if *emitPost {
+		if err := os.WriteFile(filepath.Join(*outDir, "post.py"), []byte(post.Emit(proj)), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if *out == "" {
+		_, err = fmt.Fprint(os.Stdout, rendered)
+		return err
+	}
+	return os.WriteFile(*out, []byte(rendered), 0o644)
+}
+
+// parsePortModes parses a "1=TE10,2=TE01"-style -port-mode flag value.
+func parsePortModes(s string) (map[int]string, error) {
+	overrides := map[int]string{}
+	if s == "" {
+		return overrides, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("-port-mode: malformed entry %q, want PORT=MODE", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(k))
+		if err != nil {
+			return nil, fmt.Errorf("-port-mode: invalid port number %q", k)
+		}
+		overrides[n] = strings.TrimSpace(v)
+	}
+	return overrides, nil
+}
+
+func writeAEDT(proj *model.Project, outDir, name string) error {
+	output := aedt.Emit(proj, name)
+	if err := os.WriteFile(filepath.Join(outDir, name+".aedt"), []byte(output.Project), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "run.py"), []byte(output.Driver), 0o644)
+}
@@ -0,0 +1,212 @@
+// Package aedt renders a model.Project as an ASCII Ansys Electronics
+// Desktop project (the `$begin 'AnsoftProject' ... $end` text format) plus
+// a small driver script that runs it headless, so a CST history list can
+// be migrated to HFSS without hand-rebuilding the geometry.
+package aedt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// Output is the pair of files an AEDT migration needs: the project itself
+// and a driver script that invokes ansysedt.exe against it.
+type Output struct {
+	Project string // <name>.aedt contents
+	Driver  string // run.py contents
+}
+
+// Emit renders proj as an AEDT project plus its run.py driver.
+func Emit(proj *model.Project, projectName string) Output {
+	return Output{
+		Project: emitProject(proj, projectName),
+		Driver:  emitDriver(projectName),
+	}
+}
+
+func emitProject(proj *model.Project, projectName string) string {
+	var b indentWriter
+	b.begin("AnsoftProject")
+	b.kv("Name", projectName)
+	b.kv("Version", "2021.2")
+
+	b.begin("Definitions")
+	writeMaterials(&b, proj)
+	b.end("Definitions")
+
+	b.begin("HFSSModel")
+	writeBoxes(&b, proj)
+	writeCylinders(&b, proj)
+	writeExcitations(&b, proj)
+	b.end("HFSSModel")
+
+	b.begin("AnalysisSetup")
+	writeSetup(&b, proj)
+	b.end("AnalysisSetup")
+
+	b.end("AnsoftProject")
+	return b.String()
+}
+
+func emitDriver(projectName string) string {
+	var b strings.Builder
+	b.WriteString("\"\"\"Generated by cst2py. Do not edit by hand.\"\"\"\n")
+	b.WriteString("import subprocess\n\n")
+	fmt.Fprintf(&b, "PROJECT = %q\n\n", projectName+".aedt")
+	b.WriteString("def main() -> None:\n")
+	b.WriteString("    subprocess.run([\n")
+	b.WriteString("        \"ansysedt.exe\", \"-ng\", \"-RunScriptAndExit\", PROJECT,\n")
+	b.WriteString("    ], check=True)\n\n\n")
+	b.WriteString("if __name__ == \"__main__\":\n")
+	b.WriteString("    main()\n")
+	return b.String()
+}
+
+func writeMaterials(b *indentWriter, proj *model.Project) {
+	b.begin("Materials")
+	for _, name := range materialNames(proj) {
+		b.begin(name)
+		b.kv("Name", name)
+		if name == "PEC" {
+			b.kv("Conductivity", "1e+30")
+		} else {
+			b.kv("Permittivity", "1")
+		}
+		b.end(name)
+	}
+	b.end("Materials")
+}
+
+// materialNames returns every material name writeMaterials must define:
+// proj.Materials plus any name a Box/Cylinder references that never got an
+// explicit Material With block - CST built-ins like "PEC" are assumed to
+// exist without ChangeColour ever being called on them, so the history
+// list carries no Material entry for them at all.
+func materialNames(proj *model.Project) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range proj.Materials {
+		add(name)
+	}
+	for _, br := range proj.Bricks {
+		add(br.Material)
+	}
+	for _, c := range proj.Cylinders {
+		add(c.Material)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeBoxes(b *indentWriter, proj *model.Project) {
+	b.begin("Boxes")
+	for _, br := range proj.Bricks {
+		b.begin(br.Name)
+		b.kv("Name", br.Name)
+		b.kv("Material", br.Material)
+		b.kvTuple("Position", point3(br.Xrange[0], br.Yrange[0], br.Zrange[0], "mm"))
+		b.kv("XSize", length(br.Xrange[1]-br.Xrange[0], "mm"))
+		b.kv("YSize", length(br.Yrange[1]-br.Yrange[0], "mm"))
+		b.kv("ZSize", length(br.Zrange[1]-br.Zrange[0], "mm"))
+		b.end(br.Name)
+	}
+	b.end("Boxes")
+}
+
+func writeCylinders(b *indentWriter, proj *model.Project) {
+	b.begin("Cylinders")
+	for _, c := range proj.Cylinders {
+		b.begin(c.Name)
+		b.kv("Name", c.Name)
+		b.kv("Material", c.Material)
+		b.kv("Axis", strings.ToUpper(c.Axis))
+		b.kvTuple("Center", point3(c.Xcenter, c.Ycenter, c.Zrange[0], "mm"))
+		b.kv("Radius", length(c.OuterRadius, "mm"))
+		b.kv("Height", length(c.Zrange[1]-c.Zrange[0], "mm"))
+		b.end(c.Name)
+	}
+	b.end("Cylinders")
+}
+
+func writeExcitations(b *indentWriter, proj *model.Project) {
+	b.begin("Excitations")
+	for _, p := range proj.Ports {
+		name := fmt.Sprintf("LumpedPort%d", p.Number)
+		b.begin(name)
+		b.kv("Type", "LumpedPort")
+		b.kv("Impedance", fmt.Sprintf("%gohm", p.Impedance))
+		b.kvTuple("Start", point3(p.P1.X, p.P1.Y, p.P1.Z, "mm"))
+		b.kvTuple("End", point3(p.P2.X, p.P2.Y, p.P2.Z, "mm"))
+		b.end(name)
+	}
+	b.end("Excitations")
+}
+
+func writeSetup(b *indentWriter, proj *model.Project) {
+	b.begin("Setup1")
+	center := (proj.FrequencyRange.Min + proj.FrequencyRange.Max) / 2
+	b.kv("Frequency", fmt.Sprintf("%gGHz", center))
+	b.begin("Sweeps")
+	b.begin("Sweep1")
+	b.kv("RangeStart", fmt.Sprintf("%gGHz", proj.FrequencyRange.Min))
+	b.kv("RangeEnd", fmt.Sprintf("%gGHz", proj.FrequencyRange.Max))
+	b.end("Sweep1")
+	b.end("Sweeps")
+	b.end("Setup1")
+}
+
+func point3(x, y, z float64, unit string) string {
+	return fmt.Sprintf("'%s','%s','%s'", length(x, unit), length(y, unit), length(z, unit))
+}
+
+func length(v float64, unit string) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d%s", int64(v), unit)
+	}
+	return fmt.Sprintf("%g%s", v, unit)
+}
+
+// indentWriter renders the nested $begin/$end block structure AEDT project
+// files use, indenting each level with a tab the way Ansys' own writer does.
+type indentWriter struct {
+	b     strings.Builder
+	depth int
+}
+
+func (w *indentWriter) begin(name string) {
+	w.line(fmt.Sprintf("$begin '%s'", name))
+	w.depth++
+}
+
+func (w *indentWriter) end(name string) {
+	w.depth--
+	w.line(fmt.Sprintf("$end '%s'", name))
+}
+
+func (w *indentWriter) kv(key, value string) {
+	w.line(fmt.Sprintf("%s='%s'", key, value))
+}
+
+// kvTuple writes a key whose value is itself a comma-separated list of
+// quoted components, e.g. Position='-15mm','-20mm','-300mm'.
+func (w *indentWriter) kvTuple(key, tuple string) {
+	w.line(fmt.Sprintf("%s=%s", key, tuple))
+}
+
+func (w *indentWriter) line(s string) {
+	w.b.WriteString(strings.Repeat("\t", w.depth))
+	w.b.WriteString(s)
+	w.b.WriteByte('\n')
+}
+
+func (w *indentWriter) String() string { return w.b.String() }
@@ -0,0 +1,50 @@
+package aedt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/emit/aedt"
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+func TestEmitProducesProjectAndDriver(t *testing.T) {
+	proj := model.NewProject()
+	proj.FrequencyRange = model.FrequencyRange{Min: 0, Max: 1}
+	proj.Materials["Vacuum"] = model.Material{Name: "Vacuum"}
+	proj.Bricks = []model.Brick{{Name: "solid1", Material: "Vacuum", Xrange: [2]float64{-15, 35}, Yrange: [2]float64{-20, 40}, Zrange: [2]float64{-300, 200}}}
+	proj.Ports = []model.DiscretePort{{Number: 1, Impedance: 50, P1: model.Point{X: 20, Y: 20, Z: -230}, P2: model.Point{X: 35, Y: 20, Z: -230}}}
+
+	out := aedt.Emit(proj, "Model")
+
+	for _, want := range []string{
+		"$begin 'AnsoftProject'",
+		"$begin 'solid1'",
+		"Material='Vacuum'",
+		"XSize='50mm'",
+		"$begin 'LumpedPort1'",
+		"Impedance='50ohm'",
+		"$end 'AnsoftProject'",
+	} {
+		if !strings.Contains(out.Project, want) {
+			t.Errorf("project output missing %q\n---\n%s", want, out.Project)
+		}
+	}
+	if !strings.Contains(out.Driver, `"Model.aedt"`) || !strings.Contains(out.Driver, "ansysedt.exe") {
+		t.Errorf("driver output = %q", out.Driver)
+	}
+}
+
+func TestEmitDefinesMaterialsNeverGivenAColourBlock(t *testing.T) {
+	// CST history lists only record a Material With block for materials
+	// that got an explicit ChangeColour call; a Cylinder built straight
+	// against a built-in like "PEC" never gets one.
+	proj := model.NewProject()
+	proj.Cylinders = []model.Cylinder{{Name: "solid2", Material: "PEC", Axis: "z", OuterRadius: 5, Zrange: [2]float64{0, 10}}}
+
+	out := aedt.Emit(proj, "Model")
+
+	if !strings.Contains(out.Project, "$begin 'PEC'") || !strings.Contains(out.Project, "Conductivity='1e+30'") {
+		t.Errorf("project output missing implicit PEC material definition, referenced by solid2 but never given a Material block\n---\n%s", out.Project)
+	}
+}
@@ -0,0 +1,279 @@
+// Package openems renders a model.Project as an Octave/MATLAB script
+// against the openEMS + CSXCAD API, so a CST history list can drive an
+// open FDTD simulation without the geometry being rebuilt by hand.
+package openems
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// metalMaterials lists the material names cst2py treats as PEC. CST
+// history lists don't carry conductivity in the Material With block shown
+// to the colour dialog, so this is a name heuristic rather than a property
+// read; real dielectrics should be given proper eps_r/kappa via the
+// project's actual material database before simulating.
+var metalMaterials = map[string]bool{
+	"PEC": true,
+}
+
+// Emit renders proj as a standalone openEMS Octave script.
+func Emit(proj *model.Project) string {
+	var b strings.Builder
+	b.WriteString("%% Generated by cst2py. Do not edit by hand.\n")
+	b.WriteString("close all\nclear\nclc\n\n")
+	b.WriteString("physical_constants;\n")
+	fmt.Fprintf(&b, "unit = 1e-3; %% drawing unit: %s\n\n", unitNote(proj.Units.Geometry))
+
+	writeExcitation(&b, proj)
+	writeBoundary(&b, proj.Boundary)
+	b.WriteString("\nCSX = InitCSX();\n")
+
+	writeMaterials(&b, proj)
+	writeBricks(&b, proj)
+	writeCylinders(&b, proj)
+	writePorts(&b, proj)
+	writeMonitorBox(&b, proj)
+	writeMonitors(&b, proj)
+
+	b.WriteString("\nFDTD = SetSimPath(FDTD, 'tmp');\n")
+	b.WriteString("WriteOpenEMS('tmp.xml', FDTD, CSX);\n")
+	return b.String()
+}
+
+func unitNote(geometry string) string {
+	if geometry == "" {
+		return "mm"
+	}
+	return geometry
+}
+
+func writeExcitation(b *strings.Builder, proj *model.Project) {
+	f0 := (proj.FrequencyRange.Min + proj.FrequencyRange.Max) / 2
+	fc := (proj.FrequencyRange.Max - proj.FrequencyRange.Min) / 2
+	if fc == 0 {
+		fc = proj.FrequencyRange.Max
+	}
+	fmt.Fprintf(b, "f0 = %s*1e9; %% center frequency, GHz -> Hz\n", fnum(f0))
+	fmt.Fprintf(b, "fc = %s*1e9; %% 20 dB corner frequency\n", fnum(fc))
+	b.WriteString("FDTD = InitFDTD();\n")
+	b.WriteString("FDTD = SetGaussExcite(FDTD, f0, fc);\n")
+}
+
+var boundaryMap = map[string]string{
+	"electric":    "PEC",
+	"magnetic":    "PMC",
+	"open":        "MUR",
+	"expand open": "MUR",
+}
+
+func boundaryCond(cst string) string {
+	if v, ok := boundaryMap[cst]; ok {
+		return v
+	}
+	return "PEC"
+}
+
+func writeBoundary(b *strings.Builder, bd model.Boundary) {
+	fmt.Fprintf(b, "BC = {'%s' '%s' '%s' '%s' '%s' '%s'};\n",
+		boundaryCond(bd.Xmin), boundaryCond(bd.Xmax),
+		boundaryCond(bd.Ymin), boundaryCond(bd.Ymax),
+		boundaryCond(bd.Zmin), boundaryCond(bd.Zmax))
+	b.WriteString("FDTD = SetBoundaryCond(FDTD, BC);\n")
+}
+
+func writeMaterials(b *strings.Builder, proj *model.Project) {
+	for _, name := range materialNames(proj) {
+		if metalMaterials[name] {
+			fmt.Fprintf(b, "CSX = AddMetal(CSX, %s);\n", mquote(name))
+		} else {
+			fmt.Fprintf(b, "CSX = AddMaterial(CSX, %s); %% TODO: set real eps_r/kappa, CST colour dialog carries none\n", mquote(name))
+		}
+	}
+}
+
+// materialNames returns every material name writeMaterials must register:
+// proj.Materials plus any name a Brick/Cylinder references that never got
+// an explicit Material With block - CST built-ins like "PEC" are assumed
+// to exist without ChangeColour ever being called on them, so the history
+// list carries no Material entry for them at all.
+func materialNames(proj *model.Project) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range proj.Materials {
+		add(name)
+	}
+	for _, br := range proj.Bricks {
+		add(br.Material)
+	}
+	for _, c := range proj.Cylinders {
+		add(c.Material)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeBricks(b *strings.Builder, proj *model.Project) {
+	for _, br := range proj.Bricks {
+		matVar := materialRef(br.Material)
+		fmt.Fprintf(b, "CSX = AddBox(CSX, %s, 10, [%s %s %s], [%s %s %s]); %% %s\n",
+			matVar, fnum(br.Xrange[0]), fnum(br.Yrange[0]), fnum(br.Zrange[0]),
+			fnum(br.Xrange[1]), fnum(br.Yrange[1]), fnum(br.Zrange[1]), br.Name)
+	}
+}
+
+func writeCylinders(b *strings.Builder, proj *model.Project) {
+	for _, c := range proj.Cylinders {
+		matVar := materialRef(c.Material)
+		p1, p2 := cylinderAxisPoints(c)
+		fmt.Fprintf(b, "CSX = AddCylinder(CSX, %s, 10, [%s %s %s], [%s %s %s], %s); %% %s\n",
+			matVar, fnum(p1[0]), fnum(p1[1]), fnum(p1[2]), fnum(p2[0]), fnum(p2[1]), fnum(p2[2]),
+			fnum(c.OuterRadius), c.Name)
+	}
+}
+
+func cylinderAxisPoints(c model.Cylinder) ([3]float64, [3]float64) {
+	switch strings.ToLower(c.Axis) {
+	case "x":
+		return [3]float64{c.Zrange[0], c.Xcenter, c.Ycenter}, [3]float64{c.Zrange[1], c.Xcenter, c.Ycenter}
+	case "y":
+		return [3]float64{c.Xcenter, c.Zrange[0], c.Ycenter}, [3]float64{c.Xcenter, c.Zrange[1], c.Ycenter}
+	default: // "z"
+		return [3]float64{c.Xcenter, c.Ycenter, c.Zrange[0]}, [3]float64{c.Xcenter, c.Ycenter, c.Zrange[1]}
+	}
+}
+
+func writePorts(b *strings.Builder, proj *model.Project) {
+	for i, p := range proj.Ports {
+		last := "false"
+		if i == len(proj.Ports)-1 {
+			last = "true"
+		}
+		fmt.Fprintf(b, "[CSX, port{%d}] = AddLumpedPort(CSX, 10, %d, %s, [%s %s %s], [%s %s %s], [0 0 1], %s);\n",
+			p.Number, p.Number, fnum(p.Impedance),
+			fnum(p.P1.X), fnum(p.P1.Y), fnum(p.P1.Z),
+			fnum(p.P2.X), fnum(p.P2.Y), fnum(p.P2.Z), last)
+	}
+}
+
+// dumpType maps a CST monitor field type onto the openEMS AddDump DumpType
+// enum: 0 = E-field (time or freq domain per -FileType), 1 = H-field,
+// 2 = currents/powerflow.
+func dumpType(f model.MonitorField) (string, bool) {
+	switch f {
+	case model.FieldE:
+		return "0", true
+	case model.FieldH:
+		return "1", true
+	case model.FieldPowerflow:
+		return "2", true
+	case model.FieldPowerloss:
+		return "3", true
+	default:
+		return "", false
+	}
+}
+
+// writeMonitorBox emits the `start`/`stop` bounding-box vectors that every
+// AddDump/CreateNF2FFBox call below references. CST's field and farfield
+// monitors default to the structure's overall bounding box (the "domain"
+// monitor setting), so cst2py derives one from the project's bricks and
+// cylinders rather than per-monitor extents, which the history list never
+// records.
+func writeMonitorBox(b *strings.Builder, proj *model.Project) {
+	if len(proj.Monitors) == 0 {
+		return
+	}
+	lo, hi, ok := boundingBox(proj)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(b, "\nstart = [%s %s %s];\n", fnum(lo[0]), fnum(lo[1]), fnum(lo[2]))
+	fmt.Fprintf(b, "stop = [%s %s %s];\n", fnum(hi[0]), fnum(hi[1]), fnum(hi[2]))
+}
+
+// boundingBox returns the axis-aligned extents of every brick and cylinder
+// in proj. ok is false when the project has no solid geometry to bound.
+func boundingBox(proj *model.Project) (lo, hi [3]float64, ok bool) {
+	grow := func(x, y, z float64) {
+		if !ok {
+			lo, hi = [3]float64{x, y, z}, [3]float64{x, y, z}
+			ok = true
+			return
+		}
+		lo[0], hi[0] = minf(lo[0], x), maxf(hi[0], x)
+		lo[1], hi[1] = minf(lo[1], y), maxf(hi[1], y)
+		lo[2], hi[2] = minf(lo[2], z), maxf(hi[2], z)
+	}
+	for _, br := range proj.Bricks {
+		grow(br.Xrange[0], br.Yrange[0], br.Zrange[0])
+		grow(br.Xrange[1], br.Yrange[1], br.Zrange[1])
+	}
+	for _, c := range proj.Cylinders {
+		p1, p2 := cylinderAxisPoints(c)
+		for _, p := range [2][3]float64{p1, p2} {
+			grow(p[0]-c.OuterRadius, p[1]-c.OuterRadius, p[2]-c.OuterRadius)
+			grow(p[0]+c.OuterRadius, p[1]+c.OuterRadius, p[2]+c.OuterRadius)
+		}
+	}
+	return lo, hi, ok
+}
+
+func minf(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func writeMonitors(b *strings.Builder, proj *model.Project) {
+	for _, m := range proj.Monitors {
+		if m.Field == model.FieldFarfield {
+			fmt.Fprintf(b, "[CSX, nf2ff] = CreateNF2FFBox(CSX, %s, start, stop); %% %s\n", mquote(m.Name), m.Name)
+			continue
+		}
+		dt, ok := dumpType(m.Field)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(b, "CSX = AddDump(CSX, %s, 'DumpType', %s, 'Frequency', %s*1e9);\n",
+			mquote(dumpName(m.Name)), dt, fnum(m.Frequency))
+		b.WriteString("CSX = AddBox(CSX, " + mquote(dumpName(m.Name)) + ", 0, start, stop);\n")
+	}
+}
+
+func dumpName(cstName string) string {
+	return strings.NewReplacer(" ", "_", "(", "", ")", "", ";", "_").Replace(cstName)
+}
+
+func materialRef(name string) string {
+	return mquote(name)
+}
+
+func mquote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func fnum(f float64) string {
+	if f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%g", f)
+}
@@ -0,0 +1,56 @@
+package openems_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/emit/openems"
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+func TestEmitMapsPrimitivesToOpenEMSCalls(t *testing.T) {
+	proj := model.NewProject()
+	proj.FrequencyRange = model.FrequencyRange{Min: 0, Max: 1}
+	proj.Boundary = model.Boundary{Xmin: "electric", Xmax: "electric", Ymin: "electric", Ymax: "electric", Zmin: "electric", Zmax: "electric"}
+	proj.Materials["Vacuum"] = model.Material{Name: "Vacuum"}
+	proj.Materials["PEC"] = model.Material{Name: "PEC"}
+	proj.Bricks = []model.Brick{{Name: "solid1", Material: "Vacuum", Xrange: [2]float64{-15, 35}, Yrange: [2]float64{-20, 40}, Zrange: [2]float64{-300, 200}}}
+	proj.Cylinders = []model.Cylinder{{Name: "solid2", Material: "PEC", Axis: "z", OuterRadius: 5, Xcenter: 20, Ycenter: 20, Zrange: [2]float64{-230, 120}}}
+	proj.Ports = []model.DiscretePort{{Number: 1, Impedance: 50, P1: model.Point{X: 20, Y: 20, Z: -230}, P2: model.Point{X: 35, Y: 20, Z: -230}}}
+	proj.Monitors = []model.Monitor{{Name: "e-field (f=1)", Field: model.FieldE, Frequency: 1}}
+	proj.SolverType = "HF Time Domain"
+
+	out := openems.Emit(proj)
+
+	for _, want := range []string{
+		"FDTD = InitFDTD();",
+		"FDTD = SetGaussExcite(FDTD, f0, fc);",
+		"BC = {'PEC' 'PEC' 'PEC' 'PEC' 'PEC' 'PEC'};",
+		"CSX = AddMetal(CSX, 'PEC');",
+		"CSX = AddBox(CSX, 'Vacuum', 10, [-15 -20 -300], [35 40 200]);",
+		"CSX = AddCylinder(CSX, 'PEC', 10, [20 20 -230], [20 20 120], 5);",
+		"AddLumpedPort(CSX, 10, 1, 50, [20 20 -230], [35 20 -230], [0 0 1], true);",
+		"CSX = AddDump(CSX, 'e-field_f=1', 'DumpType', 0, 'Frequency', 1*1e9);",
+		"start = [-15 -20 -300];",
+		"stop = [35 40 200];",
+		"CSX = AddBox(CSX, 'e-field_f=1', 0, start, stop);",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitRegistersMaterialsNeverGivenAColourBlock(t *testing.T) {
+	// CST history lists only record a Material With block for materials
+	// that got an explicit ChangeColour call; a Brick/Cylinder built
+	// straight against a built-in like "PEC" never gets one.
+	proj := model.NewProject()
+	proj.Cylinders = []model.Cylinder{{Name: "solid2", Material: "PEC", Axis: "z", OuterRadius: 5, Zrange: [2]float64{0, 10}}}
+
+	out := openems.Emit(proj)
+
+	if !strings.Contains(out, "CSX = AddMetal(CSX, 'PEC');") {
+		t.Errorf("output missing implicit AddMetal for PEC, referenced by solid2 but never given a Material block\n---\n%s", out)
+	}
+}
@@ -0,0 +1,148 @@
+// Package post renders a companion post-processing module for a
+// model.Project: one loader stub per Monitor definition (S-parameters,
+// farfields, volumetric field dumps) plus a report() that ties them
+// together, so cst2py's output is a runnable simulation package rather
+// than just a geometry/solver translation.
+package post
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// Emit renders proj's Monitors and the S-parameter matrix implied by its
+// ports as a standalone post.py exposing one load_* function per result
+// plus report(network, output_html). load_s_parameters is written even
+// when proj has no ports.
+func Emit(proj *model.Project) string {
+	var b strings.Builder
+	b.WriteString("\"\"\"Generated by cst2py. Do not edit by hand.\n\n")
+	b.WriteString("Loads a completed simulation's results: S-parameters via scikit-rf,\n")
+	b.WriteString("farfield patterns for cut/3D plotting, and volumetric field dumps for\n")
+	b.WriteString("ParaView. See report() for a one-shot HTML summary.\n")
+	b.WriteString("\"\"\"\n")
+	b.WriteString("import skrf as rf\n")
+	b.WriteString("import matplotlib.pyplot as plt\n\n\n")
+
+	numPorts := len(proj.Ports) + len(proj.ModalPorts)
+	writeLoadSParameters(&b, numPorts)
+
+	for _, name := range sortedFarfieldMonitors(proj.Monitors) {
+		writeFarfieldLoader(&b, name)
+	}
+	for _, m := range proj.Monitors {
+		if m.Field == model.FieldFarfield {
+			continue
+		}
+		writeFieldLoader(&b, m)
+	}
+
+	hasFarfield := len(sortedFarfieldMonitors(proj.Monitors)) > 0
+	writeReport(&b, numPorts, hasFarfield)
+	return b.String()
+}
+
+func writeLoadSParameters(b *strings.Builder, numPorts int) {
+	b.WriteString("def load_s_parameters(touchstone_path: str) -> rf.Network:\n")
+	fmt.Fprintf(b, "    \"\"\"Load the %d-port S-parameter export as a scikit-rf Network.\"\"\"\n", numPorts)
+	b.WriteString("    return rf.Network(touchstone_path)\n\n\n")
+}
+
+func writeFarfieldLoader(b *strings.Builder, name string) {
+	fmt.Fprintf(b, "def load_farfield_%s(result_dir: str):\n", sanitize(name))
+	fmt.Fprintf(b, "    \"\"\"Load the farfield pattern recorded by monitor %q.\n\n", name)
+	b.WriteString("    Returns (theta, phi, pattern) arrays suitable for polar cuts or a\n")
+	b.WriteString("    3D matplotlib surface plot.\n")
+	b.WriteString("    \"\"\"\n")
+	fmt.Fprintf(b, "    raise NotImplementedError(%q)\n\n\n", "load_farfield_"+sanitize(name)+": wire up the solver's farfield export format")
+}
+
+func writeFieldLoader(b *strings.Builder, m model.Monitor) {
+	fmt.Fprintf(b, "def load_%s(result_dir: str):\n", sanitize(m.Name))
+	fmt.Fprintf(b, "    \"\"\"Load the %s dump recorded by monitor %q as a PyVista grid\n", fieldLabel(m.Field), m.Name)
+	b.WriteString("    for visualization in ParaView.\n")
+	b.WriteString("    \"\"\"\n")
+	fmt.Fprintf(b, "    raise NotImplementedError(%q)\n\n\n", "load_"+sanitize(m.Name)+": wire up the solver's VTK/HDF5 export format")
+}
+
+func writeReport(b *strings.Builder, numPorts int, hasFarfield bool) {
+	if hasFarfield {
+		b.WriteString("def report(network: rf.Network, output_html: str, gain_dbi: list[float] | None = None) -> None:\n")
+	} else {
+		b.WriteString("def report(network: rf.Network, output_html: str) -> None:\n")
+	}
+	switch {
+	case hasFarfield && numPorts > 1:
+		b.WriteString("    \"\"\"Render |S11|, |S21| and gain vs. frequency as a small HTML summary.\n\n")
+	case hasFarfield:
+		b.WriteString("    \"\"\"Render |S11| and gain vs. frequency as a small HTML summary.\n\n")
+	case numPorts > 1:
+		b.WriteString("    \"\"\"Render |S11| and |S21| vs. frequency as a small HTML summary.\"\"\"\n")
+	default:
+		b.WriteString("    \"\"\"Render |S11| vs. frequency as a small HTML summary.\"\"\"\n")
+	}
+	if hasFarfield {
+		b.WriteString("    gain_dbi, one value per network.f sample, is the peak realized gain\n")
+		b.WriteString("    from the project's farfield monitor(s); load_farfield_* only stubs the\n")
+		b.WriteString("    solver's export format, so the caller must compute it and pass it in.\n")
+		b.WriteString("    \"\"\"\n")
+	}
+	b.WriteString("    fig, ax = plt.subplots()\n")
+	b.WriteString("    ax.plot(network.f, network.s_db[:, 0, 0], label=\"|S11|\")\n")
+	if numPorts > 1 {
+		b.WriteString("    ax.plot(network.f, network.s_db[:, 1, 0], label=\"|S21|\")\n")
+	}
+	b.WriteString("    ax.set_xlabel(\"frequency (Hz)\")\n")
+	b.WriteString("    ax.set_ylabel(\"magnitude (dB)\")\n")
+	b.WriteString("    ax.legend(loc=\"upper right\")\n")
+	if hasFarfield {
+		b.WriteString("    if gain_dbi is not None:\n")
+		b.WriteString("        gax = ax.twinx()\n")
+		b.WriteString("        gax.plot(network.f, gain_dbi, label=\"gain (dBi)\", linestyle=\"--\", color=\"black\")\n")
+		b.WriteString("        gax.set_ylabel(\"gain (dBi)\")\n")
+		b.WriteString("        gax.legend(loc=\"lower right\")\n")
+	}
+	b.WriteString("    fig.savefig(\"gain.png\")\n")
+	b.WriteString("    with open(output_html, \"w\") as f:\n")
+	b.WriteString("        f.write(f\"<html><body><h1>{network.name}</h1><img src='gain.png'></body></html>\")\n")
+}
+
+func fieldLabel(f model.MonitorField) string {
+	switch f {
+	case model.FieldE:
+		return "E-field"
+	case model.FieldH:
+		return "H-field"
+	case model.FieldPowerflow:
+		return "power flow"
+	case model.FieldPowerloss:
+		return "power loss"
+	default:
+		return string(f)
+	}
+}
+
+func sortedFarfieldMonitors(monitors []model.Monitor) []string {
+	var names []string
+	for _, m := range monitors {
+		if m.Field == model.FieldFarfield {
+			names = append(names, m.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sanitize turns a CST monitor name like "e-field (f=1)" into a valid
+// Python identifier fragment ("e_field_f1"). Like the openems backend's
+// dump naming it drops parentheses and turns spaces and ';' into '_'; it
+// also turns '-' and '.' into '_' and drops '='.
+func sanitize(name string) string {
+	r := strings.NewReplacer(
+		" ", "_", "(", "", ")", "", "-", "_", "=", "", ";", "_", ".", "_",
+	)
+	return r.Replace(name)
+}
@@ -0,0 +1,50 @@
+package post_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/emit/post"
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+func TestEmitRendersLoaderPerMonitorAndReport(t *testing.T) {
+	proj := model.NewProject()
+	proj.Ports = []model.DiscretePort{{Number: 1}, {Number: 2}}
+	proj.Monitors = []model.Monitor{
+		{Name: "e-field (f=1)", Field: model.FieldE},
+		{Name: "h-field (f=1)", Field: model.FieldH},
+		{Name: "power (f=1)", Field: model.FieldPowerflow},
+		{Name: "loss (f=1)", Field: model.FieldPowerloss},
+		{Name: "farfield (f=1)", Field: model.FieldFarfield},
+	}
+
+	out := post.Emit(proj)
+
+	for _, want := range []string{
+		"def load_s_parameters(touchstone_path: str) -> rf.Network:",
+		"def load_farfield_farfield_f1(result_dir: str):",
+		"def load_e_field_f1(result_dir: str):",
+		"def load_h_field_f1(result_dir: str):",
+		"def load_power_f1(result_dir: str):",
+		"def load_loss_f1(result_dir: str):",
+		"def report(network: rf.Network, output_html: str, gain_dbi: list[float] | None = None) -> None:",
+		`ax.plot(network.f, network.s_db[:, 1, 0], label="|S21|")`,
+		"gax.plot(network.f, gain_dbi,",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitOmitsS21WhenSinglePort(t *testing.T) {
+	proj := model.NewProject()
+	proj.Ports = []model.DiscretePort{{Number: 1}}
+
+	out := post.Emit(proj)
+
+	if strings.Contains(out, "S21") {
+		t.Errorf("single-port project shouldn't plot S21:\n%s", out)
+	}
+}
@@ -0,0 +1,72 @@
+package python
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/waveguide"
+)
+
+// writeModalPort renders a ModalPort as a standalone profile function
+// plus a project.modal_port(...) registration. The profile function holds
+// the closed-form E-field expression for the port's mode, in the
+// project's geometry units, so downstream CST-Python/openEMS/scikit-rf
+// code can sample it directly instead of re-deriving the mode.
+func writeModalPort(b *strings.Builder, mp model.ModalPort) {
+	fnName := fmt.Sprintf("_port%d_profile", mp.Number)
+	fmt.Fprintf(b, "\n    def %s(x, y):\n", fnName)
+	fmt.Fprintf(b, "        \"\"\"Analytic %s profile for port %d on waveguide %q (%s).\"\"\"\n",
+		mp.Mode, mp.Number, mp.Waveguide, mp.Standard)
+	writeProfileBody(b, mp)
+	b.WriteString("\n")
+
+	fmt.Fprintf(b, "    project.modal_port(number=%d, waveguide=%q, standard=%q, mode=%q, axis=%q, position=%s,\n",
+		mp.Number, mp.Waveguide, mp.Standard, mp.Mode, mp.Axis, fnum(mp.Position))
+	fmt.Fprintf(b, "                        kc=%s, fc_ghz=%s, beta=%s, impedance=%s,\n",
+		fnum(mp.KcMm), fnum(mp.FcGHz), fnum(mp.BetaPerMm), fnum(mp.Impedance))
+	fmt.Fprintf(b, "                        voltage_norm=%s, current_norm=%s, profile=%s)\n",
+		fnum(mp.VoltageNorm), fnum(mp.CurrentNorm), fnName)
+}
+
+func writeProfileBody(b *strings.Builder, mp model.ModalPort) {
+	kind, m, n, err := waveguide.ParseMode(mp.Mode)
+	if err != nil {
+		fmt.Fprintf(b, "        raise NotImplementedError(%q)\n", err.Error())
+		return
+	}
+	rect, circ, ok := waveguide.WaveguideByBand(mp.Standard)
+	if !ok {
+		fmt.Fprintf(b, "        raise NotImplementedError(%q)\n", "unknown waveguide standard "+mp.Standard)
+		return
+	}
+
+	b.WriteString("        import math\n")
+	switch {
+	case rect != nil:
+		fmt.Fprintf(b, "        a, b = %s, %s\n", fnum(rect.AmmWide), fnum(rect.BmmNarrow))
+		if kind == "TE" {
+			fmt.Fprintf(b, "        ex = %s * math.cos(%d * math.pi * x / a) * math.sin(%d * math.pi * y / b)\n", fnum(float64(n)), m, n)
+			fmt.Fprintf(b, "        ey = -%s * math.sin(%d * math.pi * x / a) * math.cos(%d * math.pi * y / b)\n", fnum(float64(m)), m, n)
+		} else {
+			fmt.Fprintf(b, "        ex = %s * math.sin(%d * math.pi * x / a) * math.cos(%d * math.pi * y / b)\n", fnum(float64(m)), m, n)
+			fmt.Fprintf(b, "        ey = %s * math.cos(%d * math.pi * x / a) * math.sin(%d * math.pi * y / b)\n", fnum(float64(n)), m, n)
+		}
+	case circ != nil:
+		b.WriteString("        from scipy.special import jv, jvp\n")
+		fmt.Fprintf(b, "        r_outer = %s\n", fnum(circ.RadiusMm))
+		fmt.Fprintf(b, "        kc = %s\n", fnum(mp.KcMm))
+		b.WriteString("        r = math.hypot(x, y)\n")
+		b.WriteString("        if r > r_outer:\n")
+		b.WriteString("            return 0.0, 0.0\n")
+		b.WriteString("        phi = math.atan2(y, x)\n")
+		if kind == "TE" {
+			fmt.Fprintf(b, "        ex = jvp(%d, kc * r) * math.cos(%d * phi)\n", m, m)
+			fmt.Fprintf(b, "        ey = jv(%d, kc * r) / max(kc * r, 1e-12) * math.sin(%d * phi)\n", m, m)
+		} else {
+			fmt.Fprintf(b, "        ex = jv(%d, kc * r) / max(kc * r, 1e-12) * math.sin(%d * phi)\n", m, m)
+			fmt.Fprintf(b, "        ey = jvp(%d, kc * r) * math.cos(%d * phi)\n", m, m)
+		}
+	}
+	b.WriteString("        return ex, ey\n")
+}
@@ -0,0 +1,210 @@
+// Package python renders a model.Project as a Python script against the
+// cst2py runtime (internal replay API mirroring the CST VBA object model),
+// so translated history lists read as a straight-line port of the macro.
+package python
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/sweep"
+)
+
+// Options controls emission passes that, unlike the always-on geometry and
+// solver rendering below, are opt-in heuristics with their own CLI flag.
+type Options struct {
+	// RefactorSweeps collapses monitors that a sweep.Detect pass recognizes
+	// as one-per-frequency repeats of the same monitor kind into a shared
+	// freqs list and a for loop, instead of one project.monitor(...) call
+	// per frequency.
+	RefactorSweeps bool
+}
+
+// Emit renders proj as a standalone Python module exposing build(project).
+// If proj has captured StoreParameter defaults, it also emits a Parameters
+// dataclass and widens build's signature to build(project, params).
+func Emit(proj *model.Project, opts Options) string {
+	var sw sweep.Sweep
+	monitors := proj.Monitors
+	sweeping := false
+	if opts.RefactorSweeps {
+		sw, monitors, sweeping = sweep.Detect(proj.Monitors)
+	}
+
+	var b strings.Builder
+	b.WriteString("\"\"\"Generated by cst2py. Do not edit by hand.\"\"\"\n")
+	b.WriteString("from cst2py.runtime import Project\n")
+
+	hasParams := len(proj.Parameters) > 0
+	if hasParams {
+		b.WriteString("from dataclasses import dataclass\n")
+	}
+	b.WriteString("\n\n")
+	if hasParams {
+		writeParameters(&b, proj.Parameters)
+		b.WriteString("\n\n")
+	}
+	if sweeping {
+		writeFreqsList(&b, sw.Freqs)
+		b.WriteString("\n\n")
+	}
+
+	switch {
+	case hasParams:
+		b.WriteString("def build(project: Project, params: Parameters) -> None:\n")
+	default:
+		b.WriteString("def build(project: Project) -> None:\n")
+	}
+
+	writeUnits(&b, proj.Units)
+	fmt.Fprintf(&b, "    project.frequency_range(%s, %s)\n", fnum(proj.FrequencyRange.Min), fnum(proj.FrequencyRange.Max))
+	writeBackground(&b, proj.Background)
+	writeBoundary(&b, proj.Boundary)
+	writeMesh(&b, proj.Mesh)
+
+	for _, name := range sortedMaterials(proj.Materials) {
+		writeMaterial(&b, proj.Materials[name])
+	}
+	for _, brick := range proj.Bricks {
+		writeBrick(&b, brick)
+	}
+	for _, cyl := range proj.Cylinders {
+		writeCylinder(&b, cyl)
+	}
+	for _, wg := range proj.Waveguides {
+		writeWaveguide(&b, wg)
+	}
+	for _, port := range proj.Ports {
+		writePort(&b, port)
+	}
+	for _, mp := range proj.ModalPorts {
+		writeModalPort(&b, mp)
+	}
+	if sweeping {
+		writeSweep(&b, sw)
+	}
+	for _, mon := range monitors {
+		writeMonitor(&b, mon)
+	}
+	if proj.SolverType != "" {
+		fmt.Fprintf(&b, "    project.set_solver_type(%q)\n", proj.SolverType)
+	}
+	return b.String()
+}
+
+// writeParameters renders the Parameters dataclass that hoists a project's
+// StoreParameter defaults. Note this only exposes the stored defaults for a
+// user to mutate before re-invoking build; it does not re-wire the geometry
+// calls above to read from params. The geometry calls carry the numbers the
+// translator parsed; an argument that named a parameter or held an
+// expression was not evaluated and is emitted as 0.
+func writeParameters(b *strings.Builder, params []model.Parameter) {
+	b.WriteString("@dataclass\n")
+	b.WriteString("class Parameters:\n")
+	for _, p := range params {
+		fmt.Fprintf(b, "    %s: str = %q\n", p.Name, p.Value)
+	}
+}
+
+// writeFreqsList renders the module-level freqs list a detected sweep's
+// for loop iterates over, so a user can retarget the sweep by editing one
+// line instead of hunting down every per-frequency monitor call.
+func writeFreqsList(b *strings.Builder, freqs []float64) {
+	parts := make([]string, len(freqs))
+	for i, f := range freqs {
+		parts[i] = fnum(f)
+	}
+	fmt.Fprintf(b, "freqs = [%s]\n", strings.Join(parts, ", "))
+}
+
+// writeSweep renders one project.monitor(...) call per detected template
+// inside a `for f in freqs:` loop, replacing what the source macro built as
+// repeated per-frequency Monitor With blocks.
+func writeSweep(b *strings.Builder, sw sweep.Sweep) {
+	b.WriteString("    for f in freqs:\n")
+	for _, tpl := range sw.Templates {
+		fmt.Fprintf(b, "        project.monitor(name=f%s, field=%q, frequency=f)\n",
+			pyFString(tpl.NamePattern), tpl.Field)
+	}
+}
+
+// pyFString quotes a name pattern (already containing a "{f}" placeholder)
+// for use right after Python's f string prefix, e.g. `f"e-field (f={f})"`.
+func pyFString(pattern string) string {
+	return fmt.Sprintf("%q", pattern)
+}
+
+func sortedMaterials(m map[string]model.Material) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeUnits(b *strings.Builder, u model.Units) {
+	fmt.Fprintf(b, "    project.units(geometry=%q, frequency=%q, time=%q, voltage=%q, current=%q)\n",
+		u.Geometry, u.Frequency, u.Time, u.Voltage, u.Current)
+}
+
+func writeBackground(b *strings.Builder, bg model.Background) {
+	fmt.Fprintf(b, "    project.background(type=%q)\n", bg.Type)
+}
+
+func writeBoundary(b *strings.Builder, bd model.Boundary) {
+	fmt.Fprintf(b, "    project.boundary(xmin=%q, xmax=%q, ymin=%q, ymax=%q, zmin=%q, zmax=%q)\n",
+		bd.Xmin, bd.Xmax, bd.Ymin, bd.Ymax, bd.Zmin, bd.Zmax)
+}
+
+func writeMesh(b *strings.Builder, m model.Mesh) {
+	fmt.Fprintf(b, "    project.mesh(type=%q, steps_per_wave_near=%s, steps_per_box_near=%s)\n",
+		m.Type, fnum(m.StepsPerWaveNear), fnum(m.StepsPerBoxNear))
+}
+
+func writeMaterial(b *strings.Builder, m model.Material) {
+	fmt.Fprintf(b, "    project.material(name=%q, colour=(%s, %s, %s), transparency=%s)\n",
+		m.Name, fnum(m.ColourRGB[0]), fnum(m.ColourRGB[1]), fnum(m.ColourRGB[2]), fnum(m.Transparency))
+}
+
+func writeBrick(b *strings.Builder, br model.Brick) {
+	fmt.Fprintf(b, "    project.brick(name=%q, component=%q, material=%q, xrange=(%s, %s), yrange=(%s, %s), zrange=(%s, %s))\n",
+		br.Name, br.Component, br.Material,
+		fnum(br.Xrange[0]), fnum(br.Xrange[1]),
+		fnum(br.Yrange[0]), fnum(br.Yrange[1]),
+		fnum(br.Zrange[0]), fnum(br.Zrange[1]))
+}
+
+func writeCylinder(b *strings.Builder, c model.Cylinder) {
+	fmt.Fprintf(b, "    project.cylinder(name=%q, component=%q, material=%q, axis=%q, outer_radius=%s, inner_radius=%s, center=(%s, %s), zrange=(%s, %s))\n",
+		c.Name, c.Component, c.Material, c.Axis, fnum(c.OuterRadius), fnum(c.InnerRadius),
+		fnum(c.Xcenter), fnum(c.Ycenter), fnum(c.Zrange[0]), fnum(c.Zrange[1]))
+}
+
+func writeWaveguide(b *strings.Builder, wg model.Waveguide) {
+	fmt.Fprintf(b, "    project.waveguide(name=%q, component=%q, material=%q, standard=%q, axis=%q, extent=(%s, %s))\n",
+		wg.Name, wg.Component, wg.Material, wg.Standard, wg.Axis, fnum(wg.Start), fnum(wg.End))
+}
+
+func writePort(b *strings.Builder, p model.DiscretePort) {
+	fmt.Fprintf(b, "    project.discrete_port(number=%d, impedance=%s, p1=(%s, %s, %s), p2=(%s, %s, %s))\n",
+		p.Number, fnum(p.Impedance),
+		fnum(p.P1.X), fnum(p.P1.Y), fnum(p.P1.Z),
+		fnum(p.P2.X), fnum(p.P2.Y), fnum(p.P2.Z))
+}
+
+func writeMonitor(b *strings.Builder, m model.Monitor) {
+	fmt.Fprintf(b, "    project.monitor(name=%q, field=%q, frequency=%s)\n", m.Name, m.Field, fnum(m.Frequency))
+}
+
+// fnum renders a float the way the emitted Python should read: integral
+// values without a trailing ".0" soup, otherwise Go's shortest round-trip
+// representation.
+func fnum(f float64) string {
+	if f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%g", f)
+}
@@ -0,0 +1,102 @@
+package python_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/emit/python"
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+func TestEmitRendersBrickAndMonitor(t *testing.T) {
+	proj := model.NewProject()
+	proj.FrequencyRange = model.FrequencyRange{Min: 0, Max: 1}
+	proj.Bricks = []model.Brick{{
+		Name: "solid1", Component: "component1", Material: "Vacuum",
+		Xrange: [2]float64{-15, 35}, Yrange: [2]float64{-20, 40}, Zrange: [2]float64{-300, 200},
+	}}
+	proj.Monitors = []model.Monitor{{Name: "e-field (f=1)", Field: model.FieldE, Frequency: 1}}
+	proj.SolverType = "HF Time Domain"
+
+	out := python.Emit(proj, python.Options{})
+
+	for _, want := range []string{
+		"def build(project: Project) -> None:",
+		`project.frequency_range(0, 1)`,
+		`project.brick(name="solid1"`,
+		`project.monitor(name="e-field (f=1)", field="Efield", frequency=1)`,
+		`project.set_solver_type("HF Time Domain")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitRendersModalPort(t *testing.T) {
+	proj := model.NewProject()
+	proj.ModalPorts = []model.ModalPort{{
+		Number: 1, Waveguide: "wg1", Standard: "WR-90", Mode: "TE10",
+		Axis: "z", Position: 0, KcMm: 0.137, FcGHz: 6.5, BetaPerMm: 0.2, Impedance: 500,
+	}}
+
+	out := python.Emit(proj, python.Options{})
+
+	for _, want := range []string{
+		"def _port1_profile(x, y):",
+		"a, b = 22.86, 10.16",
+		"ey = -1 * math.sin(1 * math.pi * x / a) * math.cos(0 * math.pi * y / b)",
+		`project.modal_port(number=1, waveguide="wg1", standard="WR-90", mode="TE10", axis="z", position=0,`,
+		`kc=0.137, fc_ghz=6.5, beta=0.2, impedance=500,`,
+		`voltage_norm=0, current_norm=0, profile=_port1_profile)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitRendersParametersDataclass(t *testing.T) {
+	proj := model.NewProject()
+	proj.Parameters = []model.Parameter{{Name: "wg_width", Value: "22.86"}}
+
+	out := python.Emit(proj, python.Options{})
+
+	for _, want := range []string{
+		"from dataclasses import dataclass",
+		"@dataclass\nclass Parameters:",
+		`wg_width: str = "22.86"`,
+		"def build(project: Project, params: Parameters) -> None:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+}
+
+func TestEmitRefactorsFrequencySweep(t *testing.T) {
+	proj := model.NewProject()
+	for _, f := range []float64{0.2, 0.5, 1} {
+		proj.Monitors = append(proj.Monitors,
+			model.Monitor{Name: fmt.Sprintf("e-field (f=%g)", f), Field: model.FieldE, Frequency: f},
+			model.Monitor{Name: fmt.Sprintf("h-field (f=%g)", f), Field: model.FieldH, Frequency: f},
+		)
+	}
+
+	out := python.Emit(proj, python.Options{RefactorSweeps: true})
+
+	for _, want := range []string{
+		"freqs = [0.2, 0.5, 1]",
+		"    for f in freqs:",
+		`project.monitor(name=f"e-field (f={f})", field="Efield", frequency=f)`,
+		`project.monitor(name=f"h-field (f={f})", field="Hfield", frequency=f)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `project.monitor(name="e-field (f=0.2)"`) {
+		t.Errorf("flat per-frequency monitor call should have been collapsed into the sweep loop:\n%s", out)
+	}
+}
@@ -0,0 +1,48 @@
+// Package macro parses the VBA-flavoured macro language CST Studio Suite
+// writes to a model's history list (the ".mod" files under Model/3D).
+package macro
+
+// Call is a single method invocation, either qualified ("Solver.FrequencyRange")
+// or relative to an enclosing With block (".Name").
+type Call struct {
+	Object string // e.g. "Brick", "" when relative to a With block
+	Method string // e.g. "FrequencyRange", "Reset", "Create"
+	Args   []string
+}
+
+// With is a `With <Object> ... End With` block. Calls inside it omit the
+// object and are resolved against Object.
+type With struct {
+	Object string
+	Calls  []Call
+}
+
+// Assign is a `Dim`/plain assignment statement, e.g. `sDefineAt = "0.2;0.5;1"`.
+type Assign struct {
+	Name string
+	Expr string
+}
+
+// For is a `For <Var> = <Low> To <High> ... Next` loop. CST macros also use
+// the LBound/UBound-over-Split idiom seen in frequency-list expansion; Body
+// holds the parsed statements between For and Next.
+type For struct {
+	Var  string
+	Low  string
+	High string
+	Body []Statement
+}
+
+// Statement is any top-level entry in a macro history list: a With block, a
+// bare call, a variable assignment, or a For loop.
+type Statement interface{ isStatement() }
+
+func (With) isStatement()   {}
+func (Call) isStatement()   {}
+func (Assign) isStatement() {}
+func (For) isStatement()    {}
+
+// Program is a fully parsed macro history list.
+type Program struct {
+	Statements []Statement
+}
@@ -0,0 +1,242 @@
+package macro
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse reads a CST macro history list and returns its parsed form. Parsing
+// is line-oriented: CST always emits one statement per line, so this avoids
+// a full VBA grammar in favour of recognising the handful of shapes the
+// exporter actually produces (With/End With, dotted calls, Dim/assignment,
+// For/Next).
+func Parse(r io.Reader) (*Program, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("macro: read: %w", err)
+	}
+
+	p := &parser{lines: lines}
+	stmts, err := p.parseStatements("")
+	if err != nil {
+		return nil, err
+	}
+	return &Program{Statements: stmts}, nil
+}
+
+type parser struct {
+	lines []string
+	pos   int
+}
+
+// parseStatements consumes statements until EOF or, when until is non-empty,
+// a line equal to until (case-insensitive) is reached. The terminator line
+// itself is consumed.
+func (p *parser) parseStatements(until string) ([]Statement, error) {
+	var stmts []Statement
+	for p.pos < len(p.lines) {
+		raw := p.lines[p.pos]
+		line := stripComment(raw)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			p.pos++
+			continue
+		}
+		if until != "" && strings.EqualFold(trimmed, until) {
+			p.pos++
+			return stmts, nil
+		}
+
+		switch {
+		case strings.HasPrefix(strings.ToLower(trimmed), "with "):
+			p.pos++
+			w, err := p.parseWith(strings.TrimSpace(trimmed[len("with "):]))
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, w)
+
+		case strings.HasPrefix(strings.ToLower(trimmed), "for "):
+			p.pos++
+			f, err := p.parseFor(trimmed)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, f)
+
+		case strings.HasPrefix(strings.ToLower(trimmed), "dim "):
+			p.pos++
+			stmts = append(stmts, parseDim(trimmed))
+
+		default:
+			p.pos++
+			stmts = append(stmts, parseSimple(trimmed))
+		}
+	}
+	if until != "" {
+		return nil, fmt.Errorf("macro: unexpected EOF, expected %q", until)
+	}
+	return stmts, nil
+}
+
+func (p *parser) parseWith(object string) (With, error) {
+	w := With{Object: object}
+	for p.pos < len(p.lines) {
+		raw := p.lines[p.pos]
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" {
+			p.pos++
+			continue
+		}
+		if strings.EqualFold(line, "End With") {
+			p.pos++
+			return w, nil
+		}
+		if !strings.HasPrefix(line, ".") {
+			return w, fmt.Errorf("macro: expected method call or End With inside With %s, got %q", object, line)
+		}
+		p.pos++
+		method, args := splitCall(line[1:])
+		w.Calls = append(w.Calls, Call{Method: method, Args: args})
+	}
+	return w, fmt.Errorf("macro: unterminated With %s", object)
+}
+
+func (p *parser) parseFor(header string) (For, error) {
+	// "For nIndex = LBound(aFreq) To UBound(aFreq)"
+	body := strings.TrimSpace(header[len("For "):])
+	toIdx := indexFold(body, " To ")
+	if toIdx < 0 {
+		return For{}, fmt.Errorf("macro: malformed For header %q", header)
+	}
+	head, high := body[:toIdx], strings.TrimSpace(body[toIdx+len(" To "):])
+	eq := strings.Index(head, "=")
+	if eq < 0 {
+		return For{}, fmt.Errorf("macro: malformed For header %q", header)
+	}
+	f := For{
+		Var:  strings.TrimSpace(head[:eq]),
+		Low:  strings.TrimSpace(head[eq+1:]),
+		High: high,
+	}
+	stmts, err := p.parseStatements("Next")
+	if err != nil {
+		return For{}, err
+	}
+	f.Body = stmts
+	return f, nil
+}
+
+func parseDim(line string) Assign {
+	// "Dim sDefineAt As String" -> declaration only, no value yet.
+	rest := strings.TrimSpace(line[len("dim "):])
+	name := rest
+	if i := indexFold(rest, " as "); i >= 0 {
+		name = strings.TrimSpace(rest[:i])
+	}
+	return Assign{Name: name}
+}
+
+func parseSimple(line string) Statement {
+	if eq := topLevelEquals(line); eq >= 0 {
+		return Assign{Name: strings.TrimSpace(line[:eq]), Expr: strings.TrimSpace(line[eq+1:])}
+	}
+	method, args := splitCall(line)
+	obj := ""
+	if i := strings.Index(method, "."); i >= 0 {
+		obj, method = method[:i], method[i+1:]
+	}
+	return Call{Object: obj, Method: method, Args: args}
+}
+
+// splitCall splits "Method arg1, arg2" or "Method(arg1, arg2)" into the
+// method name and its raw, comma-separated argument expressions.
+func splitCall(s string) (string, []string) {
+	s = strings.TrimSpace(s)
+	name := s
+	rest := ""
+	if i := strings.IndexAny(s, " ("); i >= 0 {
+		name = s[:i]
+		rest = strings.TrimSpace(s[i:])
+	}
+	rest = strings.TrimPrefix(rest, "(")
+	rest = strings.TrimSuffix(rest, ")")
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return name, nil
+	}
+	return name, splitArgs(rest)
+}
+
+// splitArgs splits a comma-separated argument list, ignoring commas that
+// appear inside double-quoted string literals.
+func splitArgs(s string) []string {
+	var args []string
+	var cur strings.Builder
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case r == ',' && !inQuote:
+			args = append(args, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if strings.TrimSpace(cur.String()) != "" || len(args) > 0 {
+		args = append(args, strings.TrimSpace(cur.String()))
+	}
+	return args
+}
+
+// topLevelEquals returns the index of a bare "=" (not "==" and not inside a
+// string literal) or -1 if there isn't one.
+func topLevelEquals(s string) int {
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuote = !inQuote
+		case '=':
+			if inQuote {
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '=' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func indexFold(s, sub string) int {
+	low := strings.ToLower(s)
+	return strings.Index(low, strings.ToLower(sub))
+}
+
+func stripComment(line string) string {
+	inQuote := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuote = !inQuote
+		case '\'':
+			if !inQuote {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
@@ -0,0 +1,83 @@
+package macro_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/macro"
+)
+
+func TestParseWithBlockAndBareCall(t *testing.T) {
+	src := `
+' a comment
+With Brick
+     .Reset
+     .Name "solid1"
+     .Xrange "-15", "35"
+     .Create
+End With
+
+Solver.FrequencyRange "0", "1"
+ChangeSolverType("HF Time Domain")
+`
+	prog, err := macro.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(prog.Statements) != 3 {
+		t.Fatalf("got %d statements, want 3: %#v", len(prog.Statements), prog.Statements)
+	}
+
+	w, ok := prog.Statements[0].(macro.With)
+	if !ok {
+		t.Fatalf("statement 0 = %T, want macro.With", prog.Statements[0])
+	}
+	if w.Object != "Brick" {
+		t.Errorf("With object = %q, want Brick", w.Object)
+	}
+	if len(w.Calls) != 4 {
+		t.Fatalf("got %d calls in With, want 4: %#v", len(w.Calls), w.Calls)
+	}
+	if w.Calls[1].Method != "Name" || w.Calls[1].Args[0] != `"solid1"` {
+		t.Errorf("call 1 = %+v", w.Calls[1])
+	}
+
+	freq, ok := prog.Statements[1].(macro.Call)
+	if !ok || freq.Object != "Solver" || freq.Method != "FrequencyRange" {
+		t.Fatalf("statement 1 = %#v", prog.Statements[1])
+	}
+	if len(freq.Args) != 2 || freq.Args[0] != `"0"` || freq.Args[1] != `"1"` {
+		t.Errorf("FrequencyRange args = %#v", freq.Args)
+	}
+
+	solverType, ok := prog.Statements[2].(macro.Call)
+	if !ok || solverType.Method != "ChangeSolverType" {
+		t.Fatalf("statement 2 = %#v", prog.Statements[2])
+	}
+}
+
+func TestParseForNextLoop(t *testing.T) {
+	src := `
+Dim i As Integer
+For i = LBound(a) To UBound(a)
+x = 1
+Next
+`
+	prog, err := macro.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(prog.Statements) != 2 {
+		t.Fatalf("got %d statements, want 2: %#v", len(prog.Statements), prog.Statements)
+	}
+	f, ok := prog.Statements[1].(macro.For)
+	if !ok {
+		t.Fatalf("statement 1 = %T, want macro.For", prog.Statements[1])
+	}
+	if f.Var != "i" || f.Low != "LBound(a)" || f.High != "UBound(a)" {
+		t.Errorf("For header = %+v", f)
+	}
+	if len(f.Body) != 1 {
+		t.Fatalf("For body = %#v", f.Body)
+	}
+}
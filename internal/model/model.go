@@ -0,0 +1,204 @@
+// Package model is the intermediate representation cst2py builds from a
+// parsed CST macro history list, and which every backend (Python, openEMS,
+// ...) renders from. Keeping one IR shared across backends means a new
+// emitter only has to know how to render these types, not how to read VBA.
+package model
+
+// Units holds the unit system a project was authored in. CST projects
+// default to mm/GHz/ns/V/A/Ohm but always state them explicitly.
+type Units struct {
+	Geometry    string
+	Frequency   string
+	Time        string
+	Voltage     string
+	Resistance  string
+	Inductance  string
+	Temperature string
+	Current     string
+	Conductance string
+	Capacitance string
+}
+
+// FrequencyRange is the solver's simulated band, in Units.Frequency.
+type FrequencyRange struct {
+	Min float64
+	Max float64
+}
+
+// Background is the open/PEC padding CST adds around the structure's
+// bounding box.
+type Background struct {
+	Type   string
+	Margin [3][2]float64 // [axis][min,max], axis order X,Y,Z
+}
+
+// Boundary holds the six face boundary conditions plus symmetry planes.
+type Boundary struct {
+	Xmin, Xmax string
+	Ymin, Ymax string
+	Zmin, Zmax string
+	Xsymmetry  string
+	Ysymmetry  string
+	Zsymmetry  string
+}
+
+// Mesh collects the mesh generator settings relevant to translation; CST
+// exposes many more knobs than this, but these are the ones backends need
+// to reproduce a comparable discretization.
+type Mesh struct {
+	Type               string
+	StepsPerWaveNear   float64
+	StepsPerBoxNear    float64
+	StepsPerWaveFar    float64
+	StepsPerBoxFar     float64
+	RatioLimitGeometry float64
+	MinCurvatureRefine float64
+}
+
+// Material is a named material definition. CST models line-, surface- and
+// volume materials the same way; cst2py only tracks the properties backends
+// render (colour/appearance is carried through for the Python output).
+type Material struct {
+	Name         string
+	ColourRGB    [3]float64
+	Transparency float64
+}
+
+// Brick is an axis-aligned box primitive.
+type Brick struct {
+	Name      string
+	Component string
+	Material  string
+	Xrange    [2]float64
+	Yrange    [2]float64
+	Zrange    [2]float64
+}
+
+// Cylinder is a cylindrical (or, with InnerRadius 0, solid rod) primitive
+// extruded along Axis.
+type Cylinder struct {
+	Name        string
+	Component   string
+	Material    string
+	Axis        string // "x", "y", or "z"
+	OuterRadius float64
+	InnerRadius float64
+	Xcenter     float64
+	Ycenter     float64
+	Zrange      [2]float64
+	Segments    int
+}
+
+// Point is a 3D coordinate in the project's geometry units.
+type Point struct{ X, Y, Z float64 }
+
+// DiscretePort is a lumped-element port between two points, CST's default
+// way to excite or terminate a structure.
+type DiscretePort struct {
+	Number    int
+	Type      string // e.g. "SParameter"
+	Impedance float64
+	P1, P2    Point
+}
+
+// ModalPort is a DiscretePort that the port translation layer replaced
+// with an analytic waveguide-mode excitation because it sits on a
+// recognized Waveguide's cross-section, instead of a lumped 50 ohm
+// element.
+type ModalPort struct {
+	Number      int
+	Waveguide   string // name of the Waveguide this port excites
+	Standard    string
+	Mode        string // e.g. "TE10"
+	Axis        string
+	Position    float64 // coordinate along Axis where the port plane sits
+	KcMm        float64 // cutoff wavenumber, rad/mm
+	FcGHz       float64 // cutoff frequency, GHz
+	BetaPerMm   float64 // propagation constant at the project's center frequency, rad/mm
+	Impedance   float64 // characteristic wave impedance at the center frequency, ohms
+	VoltageNorm float64 // equivalent modal voltage normalized to 1 W at the center frequency
+	CurrentNorm float64 // equivalent modal current normalized to 1 W at the center frequency
+}
+
+// MonitorField identifies what field quantity a monitor records.
+type MonitorField string
+
+const (
+	FieldE         MonitorField = "Efield"
+	FieldH         MonitorField = "Hfield"
+	FieldPowerflow MonitorField = "Powerflow"
+	FieldPowerloss MonitorField = "Powerloss"
+	FieldFarfield  MonitorField = "Farfield"
+)
+
+// Monitor is a single frequency-domain field or farfield monitor.
+type Monitor struct {
+	Name      string
+	Dimension string // e.g. "Volume"
+	Domain    string // e.g. "Frequency"
+	Field     MonitorField
+	Frequency float64
+}
+
+// Waveguide is a Brick or Cylinder that the waveguide recognizer matched
+// to a standard IEC/EIA rectangular or circular waveguide band, replacing
+// the raw primitive it came from.
+type Waveguide struct {
+	Name      string
+	Component string
+	Material  string
+	Standard  string  // e.g. "WR-90" or "WC-109"
+	Axis      string  // extrusion axis: "x", "y", or "z"
+	Start     float64 // extent along Axis, in Units.Geometry
+	End       float64
+}
+
+// TimeDomainSolver holds the transient solver parameters CST's "HF Time
+// Domain" (hexahedral TLM/FIT) solver exposes.
+type TimeDomainSolver struct {
+	Method           string
+	CalculationType  string
+	StimulationPort  string
+	StimulationMode  string
+	SteadyStateLimit float64
+	NormingImpedance float64
+}
+
+// Parameter is a named value captured from a CST StoreParameter call - the
+// macro's way of declaring a parametric variable. cst2py hoists these as
+// defaults for an emitted Parameters dataclass; it does not re-derive the
+// geometry that was built from them. The translator's expression evaluator
+// does not look up StoreParameter names and does no arithmetic, so a
+// geometry argument that names a parameter or holds an expression is not
+// resolved and reads as 0.
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// Project is the fully-resolved translation of one macro history list: the
+// geometry, materials, ports, monitors and solver setup a user built in CST,
+// ready to hand to any backend emitter.
+type Project struct {
+	Units          Units
+	FrequencyRange FrequencyRange
+	Background     Background
+	Boundary       Boundary
+	Mesh           Mesh
+	SolverType     string // e.g. "HF Time Domain"
+	Solver         TimeDomainSolver
+
+	Parameters []Parameter
+	Materials  map[string]Material
+	Bricks     []Brick
+	Cylinders  []Cylinder
+	Waveguides []Waveguide
+	Ports      []DiscretePort
+	ModalPorts []ModalPort
+	Monitors   []Monitor
+}
+
+// NewProject returns an empty Project ready for a translate pass to fill in.
+func NewProject() *Project {
+	return &Project{Materials: map[string]Material{}}
+}
@@ -0,0 +1,116 @@
+// Package sweep detects the repeated-monitor-per-frequency pattern CST's
+// exporter leaves behind when a macro built its monitors with a
+// semicolon-split frequency list or a For loop, so a backend can collapse
+// them back into a single `for f in freqs:` instead of emitting one
+// monitor call per frequency per field type.
+package sweep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// Template is one monitor "shape" that recurred across every frequency in
+// a detected sweep: its name with the frequency substituted out for the
+// placeholder "{f}", plus the fields that were constant across the group.
+type Template struct {
+	NamePattern string // e.g. "e-field (f={f})"
+	Dimension   string
+	Domain      string
+	Field       model.MonitorField
+}
+
+// Sweep is a detected frequency sweep: the shared frequency list and one
+// Template per monitor kind that was repeated once per frequency.
+type Sweep struct {
+	Freqs     []float64
+	Templates []Template
+}
+
+type groupKey struct {
+	pattern, dimension, domain string
+	field                      model.MonitorField
+}
+
+// Detect looks for two or more groups of monitors that share an identical
+// frequency list - the signature of CST's per-frequency monitor idiom -
+// and returns the sweep plus whatever monitors didn't fit the pattern.
+// Fewer than two qualifying groups isn't a sweep worth refactoring (it's
+// just a monitor that happens to be named after its frequency), so Detect
+// reports ok=false and leaves monitors untouched.
+func Detect(monitors []model.Monitor) (sw Sweep, rest []model.Monitor, ok bool) {
+	var order []groupKey
+	groups := map[groupKey][]float64{}
+	for _, m := range monitors {
+		k := keyOf(m)
+		if _, seen := groups[k]; !seen {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], m.Frequency)
+	}
+
+	var common []float64
+	var qualifying []groupKey
+	for _, cand := range order {
+		if len(groups[cand]) < 2 {
+			continue
+		}
+		qualifying = qualifying[:0]
+		for _, k := range order {
+			if len(groups[k]) >= 2 && equalFreqs(groups[cand], groups[k]) {
+				qualifying = append(qualifying, k)
+			}
+		}
+		if len(qualifying) >= 2 {
+			common = groups[cand]
+			break
+		}
+	}
+	if len(qualifying) < 2 {
+		return Sweep{}, monitors, false
+	}
+
+	qualifySet := make(map[groupKey]bool, len(qualifying))
+	templates := make([]Template, len(qualifying))
+	for i, k := range qualifying {
+		qualifySet[k] = true
+		templates[i] = Template{NamePattern: k.pattern, Dimension: k.dimension, Domain: k.domain, Field: k.field}
+	}
+	for _, m := range monitors {
+		if !qualifySet[keyOf(m)] {
+			rest = append(rest, m)
+		}
+	}
+	return Sweep{Freqs: common, Templates: templates}, rest, true
+}
+
+func keyOf(m model.Monitor) groupKey {
+	token := FormatFreq(m.Frequency)
+	pattern := strings.Replace(m.Name, token, "{f}", 1)
+	return groupKey{pattern: pattern, dimension: m.Dimension, domain: m.Domain, field: m.Field}
+}
+
+func equalFreqs(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatFreq renders a frequency the same way cst2py's emitters do, so a
+// monitor's name (built by the original macro via string concatenation)
+// can be matched back to its Frequency field.
+func FormatFreq(f float64) string {
+	if f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprintf("%g", f)
+}
@@ -0,0 +1,63 @@
+package sweep_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/sweep"
+)
+
+func monitors() []model.Monitor {
+	var ms []model.Monitor
+	for _, f := range []float64{0.2, 0.5, 1} {
+		ms = append(ms,
+			model.Monitor{Name: "e-field (f=" + sweep.FormatFreq(f) + ")", Dimension: "Volume", Domain: "Frequency", Field: model.FieldE, Frequency: f},
+			model.Monitor{Name: "h-field (f=" + sweep.FormatFreq(f) + ")", Dimension: "Volume", Domain: "Frequency", Field: model.FieldH, Frequency: f},
+		)
+	}
+	return ms
+}
+
+func TestDetectFindsSharedFrequencyList(t *testing.T) {
+	sw, rest, ok := sweep.Detect(monitors())
+	if !ok {
+		t.Fatal("Detect: want ok, got false")
+	}
+	if len(rest) != 0 {
+		t.Errorf("rest = %+v, want empty (every monitor belongs to a sweep)", rest)
+	}
+	if !reflect.DeepEqual(sw.Freqs, []float64{0.2, 0.5, 1}) {
+		t.Errorf("freqs = %v, want [0.2 0.5 1]", sw.Freqs)
+	}
+	if len(sw.Templates) != 2 {
+		t.Fatalf("templates = %+v, want 2", sw.Templates)
+	}
+	for _, tpl := range sw.Templates {
+		if tpl.NamePattern != "e-field (f={f})" && tpl.NamePattern != "h-field (f={f})" {
+			t.Errorf("unexpected template pattern %q", tpl.NamePattern)
+		}
+	}
+}
+
+func TestDetectIgnoresSingleMonitor(t *testing.T) {
+	ms := []model.Monitor{{Name: "farfield (f=1)", Field: model.FieldFarfield, Frequency: 1}}
+	_, rest, ok := sweep.Detect(ms)
+	if ok {
+		t.Error("Detect: want false for a single non-repeated monitor")
+	}
+	if !reflect.DeepEqual(rest, ms) {
+		t.Errorf("rest = %+v, want the input unchanged", rest)
+	}
+}
+
+func TestDetectLeavesNonMatchingMonitorsInRest(t *testing.T) {
+	ms := append(monitors(), model.Monitor{Name: "farfield (f=1)", Field: model.FieldFarfield, Frequency: 1})
+	_, rest, ok := sweep.Detect(ms)
+	if !ok {
+		t.Fatal("Detect: want ok, got false")
+	}
+	if len(rest) != 1 || rest[0].Name != "farfield (f=1)" {
+		t.Errorf("rest = %+v, want just the farfield monitor", rest)
+	}
+}
@@ -0,0 +1,26 @@
+package translate
+
+import "strconv"
+
+// argAt returns the i-th raw argument of a call, already evaluated against
+// env, or "" if the call doesn't have that many arguments.
+func argAt(e *env, args []string, i int) string {
+	if i < 0 || i >= len(args) {
+		return ""
+	}
+	return e.eval(args[i])
+}
+
+func argFloat(e *env, args []string, i int) float64 {
+	v, _ := strconv.ParseFloat(argAt(e, args, i), 64)
+	return v
+}
+
+func argInt(e *env, args []string, i int) int {
+	v, _ := strconv.Atoi(argAt(e, args, i))
+	return v
+}
+
+func argBool(e *env, args []string, i int) bool {
+	return argAt(e, args, i) == "True"
+}
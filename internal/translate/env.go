@@ -0,0 +1,123 @@
+package translate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// env evaluates the small slice of VBA expression syntax CST macros
+// actually use: quoted literals, "&" concatenation, 0-based array indexing
+// (aFreq(nIndex)), and the Split/LBound/UBound idiom used to expand
+// semicolon-delimited frequency lists.
+type env struct {
+	scalars map[string]string
+	arrays  map[string][]string
+}
+
+func newEnv() *env {
+	return &env{scalars: map[string]string{}, arrays: map[string][]string{}}
+}
+
+var indexRe = regexp.MustCompile(`^(\w+)\s*\(\s*([^)]*)\s*\)$`)
+
+// eval resolves a full expression to its string value, concatenating "&"
+// separated parts.
+func (e *env) eval(expr string) string {
+	var sb strings.Builder
+	for _, part := range splitTopLevel(expr, '&') {
+		sb.WriteString(e.evalAtom(strings.TrimSpace(part)))
+	}
+	return sb.String()
+}
+
+func (e *env) evalAtom(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return unquote(s)
+	}
+	if m := indexRe.FindStringSubmatch(s); m != nil {
+		if arr, ok := e.arrays[m[1]]; ok {
+			idx := e.evalInt(m[2])
+			if idx >= 0 && idx < len(arr) {
+				return arr[idx]
+			}
+			return ""
+		}
+	}
+	if v, ok := e.scalars[s]; ok {
+		return v
+	}
+	return s
+}
+
+// evalInt resolves an integer expression, including LBound(x)/UBound(x)
+// over a tracked array.
+func (e *env) evalInt(expr string) int {
+	expr = strings.TrimSpace(expr)
+	low := strings.ToLower(expr)
+	switch {
+	case strings.HasPrefix(low, "lbound("):
+		return 0
+	case strings.HasPrefix(low, "ubound("):
+		name := strings.TrimSpace(expr[strings.Index(expr, "(")+1 : len(expr)-1])
+		return len(e.arrays[name]) - 1
+	}
+	if v, ok := e.scalars[expr]; ok {
+		expr = v
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(expr))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// assignSplit evaluates a `Split(<expr>, <sep>)` call and returns the parts.
+func (e *env) assignSplit(expr string) ([]string, bool) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(strings.ToLower(expr), "split(") || !strings.HasSuffix(expr, ")") {
+		return nil, false
+	}
+	inner := expr[len("split(") : len(expr)-1]
+	args := splitTopLevel(inner, ',')
+	if len(args) != 2 {
+		return nil, false
+	}
+	value := e.eval(strings.TrimSpace(args[0]))
+	sep := unquote(strings.TrimSpace(args[1]))
+	if sep == "" {
+		return []string{value}, true
+	}
+	return strings.Split(value, sep), true
+}
+
+// splitTopLevel splits s on sep, ignoring occurrences inside double-quoted
+// string literals.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	var cur strings.Builder
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+			cur.WriteByte(c)
+		case c == sep && !inQuote:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	parts = append(parts, cur.String())
+	return parts
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,385 @@
+// Package translate interprets a parsed macro.Program and builds the
+// backend-agnostic model.Project it describes.
+package translate
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brtkrtz/cst2py/internal/macro"
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// Translate walks a parsed macro history list in order, replaying its
+// side effects (With-block object creation, variable assignment, For loop
+// expansion, Delete calls) against a fresh model.Project.
+func Translate(prog *macro.Program) (*model.Project, error) {
+	t := &translator{proj: model.NewProject(), env: newEnv()}
+	if err := t.run(prog.Statements); err != nil {
+		return nil, err
+	}
+	return t.proj, nil
+}
+
+type translator struct {
+	proj *model.Project
+	env  *env
+}
+
+func (t *translator) run(stmts []macro.Statement) error {
+	for _, s := range stmts {
+		if err := t.step(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *translator) step(s macro.Statement) error {
+	switch v := s.(type) {
+	case macro.With:
+		return t.with(v)
+	case macro.Call:
+		return t.call(v)
+	case macro.Assign:
+		t.assign(v)
+		return nil
+	case macro.For:
+		return t.forLoop(v)
+	default:
+		return fmt.Errorf("translate: unknown statement %T", s)
+	}
+}
+
+func (t *translator) assign(a macro.Assign) {
+	if a.Expr == "" {
+		return // bare Dim declaration
+	}
+	if parts, ok := t.env.assignSplit(a.Expr); ok {
+		t.env.arrays[a.Name] = parts
+		return
+	}
+	t.env.scalars[a.Name] = t.env.eval(a.Expr)
+}
+
+func (t *translator) forLoop(f macro.For) error {
+	low := t.env.evalInt(f.Low)
+	high := t.env.evalInt(f.High)
+	for i := low; i <= high; i++ {
+		t.env.scalars[f.Var] = strconv.Itoa(i)
+		if err := t.run(f.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call handles a bare, top-level qualified call such as
+// `Solver.FrequencyRange "0", "1"` or `Monitor.Delete "farfield (f=1)"`.
+func (t *translator) call(c macro.Call) error {
+	switch c.Object {
+	case "Solver":
+		if c.Method == "FrequencyRange" {
+			t.proj.FrequencyRange = model.FrequencyRange{
+				Min: argFloat(t.env, c.Args, 0),
+				Max: argFloat(t.env, c.Args, 1),
+			}
+		}
+	case "Monitor":
+		if c.Method == "Delete" {
+			name := argAt(t.env, c.Args, 0)
+			t.deleteMonitor(name)
+		}
+	case "":
+		switch c.Method {
+		case "ChangeSolverType":
+			t.proj.SolverType = argAt(t.env, c.Args, 0)
+		case "StoreParameter":
+			t.proj.Parameters = append(t.proj.Parameters, model.Parameter{
+				Name:  argAt(t.env, c.Args, 0),
+				Value: argAt(t.env, c.Args, 1),
+			})
+		}
+	}
+	// Component.New, Mesh.SetCreator, Discretizer.PBAVersion and similar
+	// bookkeeping calls don't affect the IR a backend needs and are
+	// intentionally ignored.
+	return nil
+}
+
+func (t *translator) deleteMonitor(name string) {
+	kept := t.proj.Monitors[:0]
+	for _, m := range t.proj.Monitors {
+		if m.Name != name {
+			kept = append(kept, m)
+		}
+	}
+	t.proj.Monitors = kept
+}
+
+func (t *translator) with(w macro.With) error {
+	switch w.Object {
+	case "Units":
+		t.withUnits(w.Calls)
+	case "Background":
+		t.withBackground(w.Calls)
+	case "Boundary":
+		t.withBoundary(w.Calls)
+	case "MeshSettings":
+		t.withMeshSettings(w.Calls)
+	case "Mesh":
+		t.withMesh(w.Calls)
+	case "Brick":
+		t.withBrick(w.Calls)
+	case "Cylinder":
+		t.withCylinder(w.Calls)
+	case "Material":
+		t.withMaterial(w.Calls)
+	case "DiscretePort":
+		t.withDiscretePort(w.Calls)
+	case "Monitor":
+		t.withMonitor(w.Calls)
+	case "Solver":
+		t.withSolver(w.Calls)
+	}
+	return nil
+}
+
+func (t *translator) withUnits(calls []macro.Call) {
+	u := &t.proj.Units
+	for _, c := range calls {
+		v := argAt(t.env, c.Args, 0)
+		switch c.Method {
+		case "Geometry":
+			u.Geometry = v
+		case "Frequency":
+			u.Frequency = v
+		case "Time":
+			u.Time = v
+		case "Voltage":
+			u.Voltage = v
+		case "Resistance":
+			u.Resistance = v
+		case "Inductance":
+			u.Inductance = v
+		case "TemperatureUnit":
+			u.Temperature = v
+		case "Current":
+			u.Current = v
+		case "Conductance":
+			u.Conductance = v
+		case "Capacitance":
+			u.Capacitance = v
+		}
+	}
+}
+
+func (t *translator) withBackground(calls []macro.Call) {
+	b := &t.proj.Background
+	for _, c := range calls {
+		switch c.Method {
+		case "Type":
+			b.Type = argAt(t.env, c.Args, 0)
+		case "XminSpace":
+			b.Margin[0][0] = argFloat(t.env, c.Args, 0)
+		case "XmaxSpace":
+			b.Margin[0][1] = argFloat(t.env, c.Args, 0)
+		case "YminSpace":
+			b.Margin[1][0] = argFloat(t.env, c.Args, 0)
+		case "YmaxSpace":
+			b.Margin[1][1] = argFloat(t.env, c.Args, 0)
+		case "ZminSpace":
+			b.Margin[2][0] = argFloat(t.env, c.Args, 0)
+		case "ZmaxSpace":
+			b.Margin[2][1] = argFloat(t.env, c.Args, 0)
+		}
+	}
+}
+
+func (t *translator) withBoundary(calls []macro.Call) {
+	b := &t.proj.Boundary
+	for _, c := range calls {
+		v := argAt(t.env, c.Args, 0)
+		switch c.Method {
+		case "Xmin":
+			b.Xmin = v
+		case "Xmax":
+			b.Xmax = v
+		case "Ymin":
+			b.Ymin = v
+		case "Ymax":
+			b.Ymax = v
+		case "Zmin":
+			b.Zmin = v
+		case "Zmax":
+			b.Zmax = v
+		case "Xsymmetry":
+			b.Xsymmetry = v
+		case "Ysymmetry":
+			b.Ysymmetry = v
+		case "Zsymmetry":
+			b.Zsymmetry = v
+		}
+	}
+}
+
+func (t *translator) withMeshSettings(calls []macro.Call) {
+	m := &t.proj.Mesh
+	for _, c := range calls {
+		switch c.Method {
+		case "SetMeshType":
+			m.Type = argAt(t.env, c.Args, 0)
+		case "Set":
+			switch argAt(t.env, c.Args, 0) {
+			case "StepsPerWaveNear":
+				m.StepsPerWaveNear = argFloat(t.env, c.Args, 1)
+			case "StepsPerBoxNear":
+				m.StepsPerBoxNear = argFloat(t.env, c.Args, 1)
+			case "StepsPerWaveFar":
+				m.StepsPerWaveFar = argFloat(t.env, c.Args, 1)
+			case "StepsPerBoxFar":
+				m.StepsPerBoxFar = argFloat(t.env, c.Args, 1)
+			case "RatioLimitGeometry":
+				m.RatioLimitGeometry = argFloat(t.env, c.Args, 1)
+			}
+		}
+	}
+}
+
+func (t *translator) withMesh(calls []macro.Call) {
+	for _, c := range calls {
+		if c.Method == "MeshType" {
+			t.proj.Mesh.Type = argAt(t.env, c.Args, 0)
+		}
+	}
+}
+
+func (t *translator) withBrick(calls []macro.Call) {
+	b := model.Brick{}
+	for _, c := range calls {
+		switch c.Method {
+		case "Name":
+			b.Name = argAt(t.env, c.Args, 0)
+		case "Component":
+			b.Component = argAt(t.env, c.Args, 0)
+		case "Material":
+			b.Material = argAt(t.env, c.Args, 0)
+		case "Xrange":
+			b.Xrange = [2]float64{argFloat(t.env, c.Args, 0), argFloat(t.env, c.Args, 1)}
+		case "Yrange":
+			b.Yrange = [2]float64{argFloat(t.env, c.Args, 0), argFloat(t.env, c.Args, 1)}
+		case "Zrange":
+			b.Zrange = [2]float64{argFloat(t.env, c.Args, 0), argFloat(t.env, c.Args, 1)}
+		case "Create":
+			t.proj.Bricks = append(t.proj.Bricks, b)
+		}
+	}
+}
+
+func (t *translator) withCylinder(calls []macro.Call) {
+	cyl := model.Cylinder{}
+	for _, c := range calls {
+		switch c.Method {
+		case "Name":
+			cyl.Name = argAt(t.env, c.Args, 0)
+		case "Component":
+			cyl.Component = argAt(t.env, c.Args, 0)
+		case "Material":
+			cyl.Material = argAt(t.env, c.Args, 0)
+		case "Axis":
+			cyl.Axis = argAt(t.env, c.Args, 0)
+		case "OuterRadius":
+			cyl.OuterRadius = argFloat(t.env, c.Args, 0)
+		case "InnerRadius":
+			cyl.InnerRadius = argFloat(t.env, c.Args, 0)
+		case "Xcenter":
+			cyl.Xcenter = argFloat(t.env, c.Args, 0)
+		case "Ycenter":
+			cyl.Ycenter = argFloat(t.env, c.Args, 0)
+		case "Zrange":
+			cyl.Zrange = [2]float64{argFloat(t.env, c.Args, 0), argFloat(t.env, c.Args, 1)}
+		case "Segments":
+			cyl.Segments = argInt(t.env, c.Args, 0)
+		case "Create":
+			t.proj.Cylinders = append(t.proj.Cylinders, cyl)
+		}
+	}
+}
+
+func (t *translator) withMaterial(calls []macro.Call) {
+	var name string
+	mat := model.Material{}
+	for _, c := range calls {
+		switch c.Method {
+		case "Name":
+			name = argAt(t.env, c.Args, 0)
+			mat.Name = name
+		case "Colour":
+			mat.ColourRGB = [3]float64{argFloat(t.env, c.Args, 0), argFloat(t.env, c.Args, 1), argFloat(t.env, c.Args, 2)}
+		case "Transparency":
+			mat.Transparency = argFloat(t.env, c.Args, 0)
+		case "ChangeColour", "Create":
+			t.proj.Materials[name] = mat
+		}
+	}
+}
+
+func (t *translator) withDiscretePort(calls []macro.Call) {
+	p := model.DiscretePort{}
+	for _, c := range calls {
+		switch c.Method {
+		case "PortNumber":
+			p.Number = argInt(t.env, c.Args, 0)
+		case "Type":
+			p.Type = argAt(t.env, c.Args, 0)
+		case "Impedance":
+			p.Impedance = argFloat(t.env, c.Args, 0)
+		case "SetP1":
+			p.P1 = model.Point{X: argFloat(t.env, c.Args, 1), Y: argFloat(t.env, c.Args, 2), Z: argFloat(t.env, c.Args, 3)}
+		case "SetP2":
+			p.P2 = model.Point{X: argFloat(t.env, c.Args, 1), Y: argFloat(t.env, c.Args, 2), Z: argFloat(t.env, c.Args, 3)}
+		case "Create":
+			t.proj.Ports = append(t.proj.Ports, p)
+		}
+	}
+}
+
+func (t *translator) withMonitor(calls []macro.Call) {
+	m := model.Monitor{}
+	for _, c := range calls {
+		switch c.Method {
+		case "Name":
+			m.Name = argAt(t.env, c.Args, 0)
+		case "Dimension":
+			m.Dimension = argAt(t.env, c.Args, 0)
+		case "Domain":
+			m.Domain = argAt(t.env, c.Args, 0)
+		case "FieldType":
+			m.Field = model.MonitorField(argAt(t.env, c.Args, 0))
+		case "MonitorValue":
+			m.Frequency = argFloat(t.env, c.Args, 0)
+		case "Create":
+			t.proj.Monitors = append(t.proj.Monitors, m)
+		}
+	}
+}
+
+func (t *translator) withSolver(calls []macro.Call) {
+	s := &t.proj.Solver
+	for _, c := range calls {
+		switch c.Method {
+		case "Method":
+			s.Method = argAt(t.env, c.Args, 0)
+		case "CalculationType":
+			s.CalculationType = argAt(t.env, c.Args, 0)
+		case "StimulationPort":
+			s.StimulationPort = argAt(t.env, c.Args, 0)
+		case "StimulationMode":
+			s.StimulationMode = argAt(t.env, c.Args, 0)
+		case "SteadyStateLimit":
+			s.SteadyStateLimit = argFloat(t.env, c.Args, 0)
+		case "NormingImpedance":
+			s.NormingImpedance = argFloat(t.env, c.Args, 0)
+		}
+	}
+}
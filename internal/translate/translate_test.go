@@ -0,0 +1,141 @@
+package translate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/macro"
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/translate"
+)
+
+const sample = `
+With Units
+    .Geometry "mm"
+    .Frequency "GHz"
+End With
+
+StoreParameter("wg_width", "22.86")
+
+Solver.FrequencyRange "0", "1"
+
+With Brick
+     .Reset
+     .Name "solid1"
+     .Component "component1"
+     .Material "Vacuum"
+     .Xrange "-15", "35"
+     .Yrange "-20", "40"
+     .Zrange "-300", "200"
+     .Create
+End With
+
+Dim sDefineAt As String
+sDefineAt = "0.2;0.5;1"
+Dim aFreq() As String
+aFreq = Split(sDefineAt, ";")
+
+Dim nIndex As Integer
+For nIndex = LBound(aFreq) To UBound(aFreq)
+
+Dim zz_val As String
+zz_val = aFreq (nIndex)
+
+With Monitor
+    .Reset
+    .Name "e-field ("& zz_val &")"
+    .Dimension "Volume"
+    .Domain "Frequency"
+    .FieldType "Efield"
+    .MonitorValue  zz_val
+    .Create
+End With
+
+With Monitor
+    .Reset
+    .Name "farfield ("& zz_val &")"
+    .Domain "Frequency"
+    .FieldType "Farfield"
+    .MonitorValue  zz_val
+    .Create
+End With
+
+Next
+
+ChangeSolverType("HF Time Domain")
+
+Monitor.Delete "farfield (0.2)"
+Monitor.Delete "farfield (0.5)"
+Monitor.Delete "farfield (1)"
+`
+
+func mustTranslate(t *testing.T, src string) *model.Project {
+	t.Helper()
+	prog, err := macro.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("macro.Parse: %v", err)
+	}
+	proj, err := translate.Translate(prog)
+	if err != nil {
+		t.Fatalf("translate.Translate: %v", err)
+	}
+	return proj
+}
+
+func TestTranslateBasics(t *testing.T) {
+	proj := mustTranslate(t, sample)
+
+	if proj.Units.Geometry != "mm" || proj.Units.Frequency != "GHz" {
+		t.Errorf("units = %+v, want Geometry=mm Frequency=GHz", proj.Units)
+	}
+	if proj.FrequencyRange != (model.FrequencyRange{Min: 0, Max: 1}) {
+		t.Errorf("frequency range = %+v", proj.FrequencyRange)
+	}
+	if len(proj.Bricks) != 1 || proj.Bricks[0].Name != "solid1" {
+		t.Fatalf("bricks = %+v", proj.Bricks)
+	}
+	if got, want := proj.Bricks[0].Xrange, [2]float64{-15, 35}; got != want {
+		t.Errorf("brick xrange = %v, want %v", got, want)
+	}
+	if proj.SolverType != "HF Time Domain" {
+		t.Errorf("solver type = %q", proj.SolverType)
+	}
+}
+
+func TestTranslateFrequencyLoopExpandsMonitors(t *testing.T) {
+	proj := mustTranslate(t, sample)
+
+	if len(proj.Monitors) != 3 {
+		t.Fatalf("monitors = %d, want 3 (one e-field per frequency)", len(proj.Monitors))
+	}
+	want := []float64{0.2, 0.5, 1}
+	for i, m := range proj.Monitors {
+		if m.Field != model.FieldE {
+			t.Errorf("monitor %d field = %q, want Efield", i, m.Field)
+		}
+		if m.Frequency != want[i] {
+			t.Errorf("monitor %d frequency = %v, want %v", i, m.Frequency, want[i])
+		}
+	}
+}
+
+func TestTranslateCapturesStoreParameter(t *testing.T) {
+	proj := mustTranslate(t, sample)
+
+	if len(proj.Parameters) != 1 {
+		t.Fatalf("parameters = %+v, want 1", proj.Parameters)
+	}
+	if got := proj.Parameters[0]; got.Name != "wg_width" || got.Value != "22.86" {
+		t.Errorf("parameter = %+v, want {wg_width 22.86}", got)
+	}
+}
+
+func TestTranslateDeletesFarfieldMonitors(t *testing.T) {
+	proj := mustTranslate(t, sample)
+
+	for _, m := range proj.Monitors {
+		if m.Field == model.FieldFarfield {
+			t.Errorf("farfield monitor %q should have been removed by Monitor.Delete", m.Name)
+		}
+	}
+}
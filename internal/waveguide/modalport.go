@@ -0,0 +1,80 @@
+package waveguide
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// planeTolerance is how close (in the project's geometry units) a
+// DiscretePort's plane has to sit to a recognized Waveguide's end face to
+// be considered "on" that guide.
+const planeTolerance = 1e-6
+
+// Modalize replaces each DiscretePort that sits on a recognized
+// model.Waveguide's cross-section with an analytic model.ModalPort,
+// choosing the dominant mode unless overrides names one for that port
+// number. It must run after Recognize has populated proj.Waveguides.
+func Modalize(proj *model.Project, overrides map[int]string) ([]string, error) {
+	centerGHz := (proj.FrequencyRange.Min + proj.FrequencyRange.Max) / 2
+
+	var kept []model.DiscretePort
+	var warnings []string
+	for _, p := range proj.Ports {
+		wg, plane, ok := findGuide(proj.Waveguides, p)
+		if !ok {
+			kept = append(kept, p)
+			continue
+		}
+
+		mode, err := Resolve(wg.Standard, overrides[p.Number])
+		if err != nil {
+			return nil, fmt.Errorf("waveguide: port %d: %w", p.Number, err)
+		}
+		beta, propagating := mode.Propagation(centerGHz)
+		if !propagating {
+			warnings = append(warnings, fmt.Sprintf(
+				"port %d: %s on %s is below cutoff (fc=%.3g GHz) at the %.3g GHz center frequency",
+				p.Number, mode.Name, wg.Standard, mode.FcGHz, centerGHz))
+		}
+
+		voltage, current := mode.PowerNorm(centerGHz)
+		proj.ModalPorts = append(proj.ModalPorts, model.ModalPort{
+			Number: p.Number, Waveguide: wg.Name, Standard: wg.Standard,
+			Mode: mode.Name, Axis: wg.Axis, Position: plane,
+			KcMm: mode.KcMm, FcGHz: mode.FcGHz,
+			BetaPerMm: beta, Impedance: mode.WaveImpedance(centerGHz),
+			VoltageNorm: voltage, CurrentNorm: current,
+		})
+	}
+	proj.Ports = kept
+	return warnings, nil
+}
+
+// findGuide returns the first Waveguide whose Start or End plane a
+// DiscretePort sits in - one of the port's two endpoints must have its
+// coordinate along the guide's axis within planeTolerance of Start or End -
+// and that coordinate. The endpoint's position across the plane is not
+// checked against the guide's cross-section.
+func findGuide(guides []model.Waveguide, p model.DiscretePort) (model.Waveguide, float64, bool) {
+	for _, wg := range guides {
+		for _, coord := range []float64{axisCoord(wg.Axis, p.P1), axisCoord(wg.Axis, p.P2)} {
+			if math.Abs(coord-wg.Start) <= planeTolerance || math.Abs(coord-wg.End) <= planeTolerance {
+				return wg, coord, true
+			}
+		}
+	}
+	return model.Waveguide{}, 0, false
+}
+
+func axisCoord(axis string, pt model.Point) float64 {
+	switch axis {
+	case "x":
+		return pt.X
+	case "y":
+		return pt.Y
+	default:
+		return pt.Z
+	}
+}
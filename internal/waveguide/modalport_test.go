@@ -0,0 +1,63 @@
+package waveguide_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/waveguide"
+)
+
+func TestModalizeReplacesPortOnRecognizedGuide(t *testing.T) {
+	proj := model.NewProject()
+	proj.FrequencyRange = model.FrequencyRange{Min: 8.2, Max: 12.4}
+	proj.Bricks = []model.Brick{{
+		Name:   "wg1",
+		Xrange: [2]float64{0, 22.86}, Yrange: [2]float64{0, 10.16}, Zrange: [2]float64{0, 100},
+	}}
+	proj.Ports = []model.DiscretePort{{
+		Number: 1, Impedance: 50,
+		P1: model.Point{X: 0, Y: 0, Z: 0}, P2: model.Point{X: 22.86, Y: 0, Z: 0},
+	}}
+	waveguide.Recognize(proj)
+
+	warnings, err := waveguide.Modalize(proj, nil)
+	if err != nil {
+		t.Fatalf("Modalize: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none (center frequency is within WR-90's band)", warnings)
+	}
+	if len(proj.Ports) != 0 {
+		t.Fatalf("port should have been consumed, got %+v", proj.Ports)
+	}
+	if len(proj.ModalPorts) != 1 {
+		t.Fatalf("modal ports = %+v, want 1", proj.ModalPorts)
+	}
+	mp := proj.ModalPorts[0]
+	if mp.Mode != "TE10" || mp.Standard != "WR-90" {
+		t.Errorf("modal port = %+v, want mode=TE10 standard=WR-90", mp)
+	}
+	if mp.BetaPerMm <= 0 {
+		t.Errorf("beta = %g, want > 0 (center frequency is above cutoff)", mp.BetaPerMm)
+	}
+	if mp.VoltageNorm <= 0 || mp.CurrentNorm <= 0 {
+		t.Errorf("voltage/current norm = %g/%g, want both > 0 (mode propagates)", mp.VoltageNorm, mp.CurrentNorm)
+	}
+	if math.Abs(mp.VoltageNorm*mp.CurrentNorm-1) > 1e-9 {
+		t.Errorf("voltage_norm*current_norm = %g, want 1 (unit power normalization)", mp.VoltageNorm*mp.CurrentNorm)
+	}
+}
+
+func TestModalizeLeavesNonGuidePortsAlone(t *testing.T) {
+	proj := model.NewProject()
+	proj.Ports = []model.DiscretePort{{Number: 1, Impedance: 50}}
+
+	_, err := waveguide.Modalize(proj, nil)
+	if err != nil {
+		t.Fatalf("Modalize: %v", err)
+	}
+	if len(proj.Ports) != 1 || len(proj.ModalPorts) != 0 {
+		t.Errorf("port without a waveguide should stay discrete, got ports=%+v modal=%+v", proj.Ports, proj.ModalPorts)
+	}
+}
@@ -0,0 +1,188 @@
+package waveguide
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// cMmPerNs is the speed of light in mm/ns, which equals mm·GHz - the unit
+// combination every CST project in this codebase already uses.
+const cMmPerNs = 299.792458
+
+// Mode is a waveguide mode fully resolved for one guide's dimensions: its
+// cutoff wavenumber/frequency and, given an operating frequency, its
+// propagation constant and characteristic wave impedance.
+type Mode struct {
+	Name  string // e.g. "TE10", "TE11", "TM01"
+	Kind  string // "TE" or "TM"
+	M, N  int
+	KcMm  float64 // cutoff wavenumber, rad/mm
+	FcGHz float64
+}
+
+var modeRe = regexp.MustCompile(`^(TE|TM)(\d)(\d)$`)
+
+// ParseMode splits a mode name like "TE10" into its kind and indices.
+func ParseMode(name string) (kind string, m, n int, err error) {
+	g := modeRe.FindStringSubmatch(name)
+	if g == nil {
+		return "", 0, 0, fmt.Errorf("waveguide: invalid mode name %q", name)
+	}
+	m, _ = strconv.Atoi(g[2])
+	n, _ = strconv.Atoi(g[3])
+	return g[1], m, n, nil
+}
+
+// DominantMode returns the lowest-cutoff mode for a waveguide built to
+// standard: TE10 for rectangular guides, TE11 for circular ones.
+func DominantMode(standard string) (string, error) {
+	rect, circ, _ := WaveguideByBand(standard)
+	switch {
+	case rect != nil:
+		return "TE10", nil
+	case circ != nil:
+		return "TE11", nil
+	default:
+		return "", fmt.Errorf("waveguide: unknown standard %q", standard)
+	}
+}
+
+// Resolve computes the cutoff wavenumber and frequency of modeName on the
+// guide built to standard; Mode.Propagation and Mode.WaveImpedance then give
+// its behaviour at an operating frequency. An empty modeName selects the
+// dominant mode. For a circular guide the first index of the mode name is
+// the Bessel order and the second the root number (TE11, TM01).
+func Resolve(standard, modeName string) (Mode, error) {
+	if modeName == "" {
+		var err error
+		modeName, err = DominantMode(standard)
+		if err != nil {
+			return Mode{}, err
+		}
+	}
+	kind, m, n, err := ParseMode(modeName)
+	if err != nil {
+		return Mode{}, err
+	}
+
+	rect, circ, ok := WaveguideByBand(standard)
+	if !ok {
+		return Mode{}, fmt.Errorf("waveguide: unknown standard %q", standard)
+	}
+
+	var kc float64
+	switch {
+	case rect != nil:
+		kc = math.Sqrt(math.Pow(float64(m)*math.Pi/rect.AmmWide, 2) + math.Pow(float64(n)*math.Pi/rect.BmmNarrow, 2))
+	case circ != nil:
+		root, err := besselRoot(kind, m, n)
+		if err != nil {
+			return Mode{}, err
+		}
+		kc = root / circ.RadiusMm
+	}
+
+	return Mode{
+		Name: modeName, Kind: kind, M: m, N: n,
+		KcMm: kc, FcGHz: cMmPerNs * kc / (2 * math.Pi),
+	}, nil
+}
+
+// Propagation returns the mode's propagation constant beta (rad/mm) at
+// fGHz, and whether the mode propagates at all (fGHz above cutoff).
+func (mo Mode) Propagation(fGHz float64) (beta float64, propagating bool) {
+	k := 2 * math.Pi * fGHz / cMmPerNs
+	arg := k*k - mo.KcMm*mo.KcMm
+	if arg <= 0 {
+		return 0, false
+	}
+	return math.Sqrt(arg), true
+}
+
+// WaveImpedance returns the mode's characteristic wave impedance in ohms
+// at fGHz (TE: eta0*k/beta, TM: eta0*beta/k), or 0 if the mode is cut off.
+func (mo Mode) WaveImpedance(fGHz float64) float64 {
+	const eta0 = 376.730313668 // free-space wave impedance, ohms
+	beta, ok := mo.Propagation(fGHz)
+	if !ok {
+		return 0
+	}
+	k := 2 * math.Pi * fGHz / cMmPerNs
+	if mo.Kind == "TM" {
+		return eta0 * beta / k
+	}
+	return eta0 * k / beta
+}
+
+// PowerNorm returns the equivalent voltage and current that carry unit
+// (1 W) average power in the mode at fGHz - the V/I normalization CST and
+// HFSS use to map a modal port's field amplitude onto a calibrated
+// S-parameter reference impedance, with Z the mode's characteristic wave
+// impedance: V = sqrt(Z), I = 1/sqrt(Z), so that the normalized power
+// P = V*I* is 1 W. Returns 0, 0 if the mode is cut off at fGHz.
+func (mo Mode) PowerNorm(fGHz float64) (voltage, current float64) {
+	z := mo.WaveImpedance(fGHz)
+	if z == 0 {
+		return 0, 0
+	}
+	return math.Sqrt(z), 1 / math.Sqrt(z)
+}
+
+// besselRoot returns the m-th positive root of J'_n (TE modes, the
+// derivative of the Bessel function of the first kind) or J_n (TM modes),
+// found by scanning for sign changes and refining with bisection. Go's
+// math.Jn covers the function values; there is no closed form for its
+// zeros, so root-finding replaces what would otherwise be a hardcoded
+// table of chi'_{n,m} constants.
+func besselRoot(kind string, n, m int) (float64, error) {
+	f := besselDerivative(n)
+	if kind == "TM" {
+		f = func(x float64) float64 { return math.Jn(n, x) }
+	}
+
+	const (
+		step   = 1e-3
+		xMax   = 30.0
+		xStart = 1e-6 // avoid the n=0 derivative's removable singularity at 0
+	)
+	found := 0
+	prev := f(xStart)
+	for x := xStart + step; x <= xMax; x += step {
+		cur := f(x)
+		if prev == 0 || (prev > 0) != (cur > 0) {
+			found++
+			if found == m {
+				return bisect(f, x-step, x), nil
+			}
+		}
+		prev = cur
+	}
+	return 0, fmt.Errorf("waveguide: could not find root %d of %s'_%d within [0,%g]", m, kind, n, xMax)
+}
+
+// besselDerivative returns J'_n via the standard recurrence
+// J'_n(x) = J_{n-1}(x) - (n/x) J_n(x), with J'_0(x) = -J_1(x).
+func besselDerivative(n int) func(float64) float64 {
+	if n == 0 {
+		return func(x float64) float64 { return -math.J1(x) }
+	}
+	return func(x float64) float64 {
+		return math.Jn(n-1, x) - float64(n)/x*math.Jn(n, x)
+	}
+}
+
+func bisect(f func(float64) float64, lo, hi float64) float64 {
+	flo := f(lo)
+	for i := 0; i < 60; i++ {
+		mid := (lo + hi) / 2
+		fmid := f(mid)
+		if (fmid > 0) == (flo > 0) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
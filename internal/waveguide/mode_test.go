@@ -0,0 +1,51 @@
+package waveguide_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/waveguide"
+)
+
+func TestResolveRectangularTE10CutoffBelowBandMin(t *testing.T) {
+	mode, err := waveguide.Resolve("WR-90", "")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if mode.Name != "TE10" {
+		t.Errorf("dominant mode = %q, want TE10", mode.Name)
+	}
+	// WR-90's published fmin (8.2 GHz) sits about 25% above TE10 cutoff
+	// (c/2a, ~6.56 GHz).
+	if mode.FcGHz <= 0 || mode.FcGHz >= 8.2 {
+		t.Errorf("fc = %g GHz, want in (0, 8.2)", mode.FcGHz)
+	}
+}
+
+func TestResolveCircularTE11CutoffIsPositive(t *testing.T) {
+	mode, err := waveguide.Resolve("WC-109", "TE11")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if mode.FcGHz <= 0 {
+		t.Errorf("fc = %g, want > 0", mode.FcGHz)
+	}
+	// chi'_{1,1} is the well-known ~1.8412 root of J1'.
+	wantKc := 1.8412 / 13.84
+	if math.Abs(mode.KcMm-wantKc)/wantKc > 0.01 {
+		t.Errorf("kc = %g, want ~%g", mode.KcMm, wantKc)
+	}
+}
+
+func TestPropagationBelowCutoff(t *testing.T) {
+	mode, err := waveguide.Resolve("WR-90", "TE10")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if _, ok := mode.Propagation(1); ok {
+		t.Errorf("1 GHz should be below WR-90 TE10 cutoff (%g GHz)", mode.FcGHz)
+	}
+	if _, ok := mode.Propagation(10); !ok {
+		t.Errorf("10 GHz should propagate on WR-90 TE10 (cutoff %g GHz)", mode.FcGHz)
+	}
+}
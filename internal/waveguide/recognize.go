@@ -0,0 +1,155 @@
+package waveguide
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+)
+
+// tolerance is how close a primitive's cross-section has to be to a
+// standard band's published dimensions, as a fraction of that dimension,
+// to count as a match. Drawn geometry is never exactly on spec, but a
+// waveguide built to a named standard is within a fraction of a percent.
+const tolerance = 0.01
+
+// Recognize scans proj's Bricks and Cylinders for ones matching a standard
+// rectangular or circular waveguide cross-section, replacing each match
+// with a model.Waveguide and appending it to proj.Waveguides. For each
+// recognized waveguide it returns one warning per monitor sampling a
+// frequency outside that standard's recommended operating range, plus one
+// if the solver's frequency range extends outside it.
+func Recognize(proj *model.Project) []string {
+	var warnings []string
+
+	var keptBricks []model.Brick
+	for _, br := range proj.Bricks {
+		wg, ok := matchBrick(br)
+		if !ok {
+			keptBricks = append(keptBricks, br)
+			continue
+		}
+		proj.Waveguides = append(proj.Waveguides, wg)
+		warnings = append(warnings, checkMonitors(proj, wg)...)
+	}
+	proj.Bricks = keptBricks
+
+	var keptCylinders []model.Cylinder
+	for _, cyl := range proj.Cylinders {
+		wg, ok := matchCylinder(cyl)
+		if !ok {
+			keptCylinders = append(keptCylinders, cyl)
+			continue
+		}
+		proj.Waveguides = append(proj.Waveguides, wg)
+		warnings = append(warnings, checkMonitors(proj, wg)...)
+	}
+	proj.Cylinders = keptCylinders
+
+	return warnings
+}
+
+// matchBrick recognizes a Brick as a rectangular waveguide: the two
+// cross-section dimensions (the axes that aren't the longest, extruded
+// one) must match a standard band's a×b within tolerance.
+func matchBrick(br model.Brick) (model.Waveguide, bool) {
+	lx := span(br.Xrange)
+	ly := span(br.Yrange)
+	lz := span(br.Zrange)
+
+	axis, a, b := longestAxis(lx, ly, lz)
+	var start, end float64
+	switch axis {
+	case "x":
+		start, end = br.Xrange[0], br.Xrange[1]
+	case "y":
+		start, end = br.Yrange[0], br.Yrange[1]
+	default:
+		start, end = br.Zrange[0], br.Zrange[1]
+	}
+
+	for _, band := range RectangularBands {
+		if withinTolerance(a, band.AmmWide) && withinTolerance(b, band.BmmNarrow) {
+			return model.Waveguide{
+				Name: br.Name, Component: br.Component, Material: br.Material,
+				Standard: band.Band, Axis: axis, Start: start, End: end,
+			}, true
+		}
+	}
+	return model.Waveguide{}, false
+}
+
+// matchCylinder recognizes a Cylinder as a circular waveguide: it must be
+// solid (InnerRadius 0) and its radius must match a standard band within
+// tolerance.
+func matchCylinder(cyl model.Cylinder) (model.Waveguide, bool) {
+	if cyl.InnerRadius != 0 {
+		return model.Waveguide{}, false
+	}
+	for _, band := range CircularBands {
+		if withinTolerance(cyl.OuterRadius, band.RadiusMm) {
+			return model.Waveguide{
+				Name: cyl.Name, Component: cyl.Component, Material: cyl.Material,
+				Standard: band.Band, Axis: cyl.Axis, Start: cyl.Zrange[0], End: cyl.Zrange[1],
+			}, true
+		}
+	}
+	return model.Waveguide{}, false
+}
+
+// checkMonitors warns about any monitor sampling a frequency outside wg's
+// recommended operating band, plus the solver's overall FrequencyRange.
+func checkMonitors(proj *model.Project, wg model.Waveguide) []string {
+	fmin, fmax, ok := bandRange(wg.Standard)
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	if proj.FrequencyRange.Min < fmin || proj.FrequencyRange.Max > fmax {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s (%s): solver frequency range %g-%g GHz falls outside the recommended %g-%g GHz band",
+			wg.Name, wg.Standard, proj.FrequencyRange.Min, proj.FrequencyRange.Max, fmin, fmax))
+	}
+	for _, m := range proj.Monitors {
+		if m.Frequency < fmin || m.Frequency > fmax {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s (%s): monitor %q at %g GHz falls outside the recommended %g-%g GHz band",
+				wg.Name, wg.Standard, m.Name, m.Frequency, fmin, fmax))
+		}
+	}
+	return warnings
+}
+
+func bandRange(standard string) (fmin, fmax float64, ok bool) {
+	rect, circ, ok := WaveguideByBand(standard)
+	switch {
+	case rect != nil:
+		return rect.FminGHz, rect.FmaxGHz, true
+	case circ != nil:
+		return circ.FminGHz, circ.FmaxGHz, true
+	default:
+		return 0, 0, ok
+	}
+}
+
+func span(r [2]float64) float64 { return math.Abs(r[1] - r[0]) }
+
+// longestAxis returns the name of the largest of the three spans (the
+// extrusion direction) and the other two, sorted wide-then-narrow.
+func longestAxis(lx, ly, lz float64) (axis string, wide, narrow float64) {
+	switch {
+	case lx >= ly && lx >= lz:
+		return "x", math.Max(ly, lz), math.Min(ly, lz)
+	case ly >= lx && ly >= lz:
+		return "y", math.Max(lx, lz), math.Min(lx, lz)
+	default:
+		return "z", math.Max(lx, ly), math.Min(lx, ly)
+	}
+}
+
+func withinTolerance(got, want float64) bool {
+	if want == 0 {
+		return got == 0
+	}
+	return math.Abs(got-want)/want <= tolerance
+}
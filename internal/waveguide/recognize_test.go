@@ -0,0 +1,54 @@
+package waveguide_test
+
+import (
+	"testing"
+
+	"github.com/brtkrtz/cst2py/internal/model"
+	"github.com/brtkrtz/cst2py/internal/waveguide"
+)
+
+func TestRecognizeMatchesRectangularBrick(t *testing.T) {
+	proj := model.NewProject()
+	proj.FrequencyRange = model.FrequencyRange{Min: 8.2, Max: 12.4}
+	proj.Bricks = []model.Brick{{
+		Name: "wg1", Component: "c1", Material: "PEC",
+		Xrange: [2]float64{0, 22.86},
+		Yrange: [2]float64{0, 10.16},
+		Zrange: [2]float64{0, 100},
+	}}
+	proj.Monitors = []model.Monitor{{Name: "m1", Frequency: 20}} // out of band
+
+	warnings := waveguide.Recognize(proj)
+
+	if len(proj.Bricks) != 0 {
+		t.Fatalf("brick should have been consumed, got %+v", proj.Bricks)
+	}
+	if len(proj.Waveguides) != 1 {
+		t.Fatalf("waveguides = %+v, want 1", proj.Waveguides)
+	}
+	wg := proj.Waveguides[0]
+	if wg.Standard != "WR-90" || wg.Axis != "z" {
+		t.Errorf("recognized waveguide = %+v, want standard=WR-90 axis=z", wg)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1 (monitor m1 at 20GHz is out of WR-90's band)", warnings)
+	}
+}
+
+func TestRecognizeLeavesNonStandardBrickAlone(t *testing.T) {
+	proj := model.NewProject()
+	proj.Bricks = []model.Brick{{Name: "solid1", Xrange: [2]float64{-15, 35}, Yrange: [2]float64{-20, 40}, Zrange: [2]float64{-300, 200}}}
+
+	waveguide.Recognize(proj)
+
+	if len(proj.Bricks) != 1 || len(proj.Waveguides) != 0 {
+		t.Errorf("non-standard brick should be left untouched, got bricks=%+v waveguides=%+v", proj.Bricks, proj.Waveguides)
+	}
+}
+
+func TestRectangularByFreq(t *testing.T) {
+	band, ok := waveguide.RectangularByFreq(10)
+	if !ok || band.Band != "WR-90" {
+		t.Errorf("RectangularByFreq(10) = %+v, %v, want WR-90", band, ok)
+	}
+}
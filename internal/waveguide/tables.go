@@ -0,0 +1,88 @@
+// Package waveguide recognizes standard rectangular and circular
+// waveguide cross-sections among a project's raw primitives, and provides
+// lookup tables for the IEC/EIA standard bands so cst2py and its users
+// don't have to hand-carry a×b / radius numbers around.
+package waveguide
+
+// Rectangular is one IEC/EIA standard rectangular waveguide band: its
+// recommended operating range and internal cross-section in mm.
+type Rectangular struct {
+	Band      string // e.g. "WR-90"
+	FminGHz   float64
+	FmaxGHz   float64
+	AmmWide   float64 // inner a dimension (broad wall), mm
+	BmmNarrow float64 // inner b dimension (narrow wall), mm
+}
+
+// Circular is one standard circular waveguide size: its recommended
+// operating range (dominant TE11 mode) and internal radius in mm.
+type Circular struct {
+	Band     string // e.g. "WC-281"
+	FminGHz  float64
+	FmaxGHz  float64
+	RadiusMm float64
+}
+
+// RectangularBands is the common IEC/EIA rectangular waveguide series,
+// ordered from lowest to highest band. Figures are the widely published
+// recommended TE10 operating ranges and internal a×b dimensions.
+var RectangularBands = []Rectangular{
+	{Band: "WR-650", FminGHz: 1.12, FmaxGHz: 1.70, AmmWide: 165.1, BmmNarrow: 82.55},
+	{Band: "WR-430", FminGHz: 1.70, FmaxGHz: 2.60, AmmWide: 109.22, BmmNarrow: 54.61},
+	{Band: "WR-284", FminGHz: 2.60, FmaxGHz: 3.95, AmmWide: 72.14, BmmNarrow: 34.04},
+	{Band: "WR-187", FminGHz: 3.95, FmaxGHz: 5.85, AmmWide: 47.55, BmmNarrow: 22.15},
+	{Band: "WR-137", FminGHz: 5.85, FmaxGHz: 8.20, AmmWide: 34.85, BmmNarrow: 15.80},
+	{Band: "WR-90", FminGHz: 8.20, FmaxGHz: 12.40, AmmWide: 22.86, BmmNarrow: 10.16},
+	{Band: "WR-62", FminGHz: 12.40, FmaxGHz: 18.00, AmmWide: 15.80, BmmNarrow: 7.90},
+	{Band: "WR-42", FminGHz: 18.00, FmaxGHz: 26.50, AmmWide: 10.67, BmmNarrow: 4.32},
+	{Band: "WR-28", FminGHz: 26.50, FmaxGHz: 40.00, AmmWide: 7.11, BmmNarrow: 3.56},
+}
+
+// CircularBands is a small selection of common circular waveguide sizes
+// and their recommended TE11 operating ranges.
+var CircularBands = []Circular{
+	{Band: "WC-281", FminGHz: 3.80, FmaxGHz: 5.50, RadiusMm: 35.71},
+	{Band: "WC-166", FminGHz: 6.20, FmaxGHz: 9.00, RadiusMm: 21.08},
+	{Band: "WC-109", FminGHz: 8.60, FmaxGHz: 12.40, RadiusMm: 13.84},
+	{Band: "WC-84", FminGHz: 11.90, FmaxGHz: 17.10, RadiusMm: 10.69},
+}
+
+// WaveguideByBand returns the rectangular or circular band named name
+// (e.g. "WR-90" or "WC-109"), whichever table has it.
+func WaveguideByBand(name string) (rect *Rectangular, circ *Circular, ok bool) {
+	for i := range RectangularBands {
+		if RectangularBands[i].Band == name {
+			return &RectangularBands[i], nil, true
+		}
+	}
+	for i := range CircularBands {
+		if CircularBands[i].Band == name {
+			return nil, &CircularBands[i], true
+		}
+	}
+	return nil, nil, false
+}
+
+// RectangularByFreq returns the rectangular band whose recommended range
+// contains fGHz, if any.
+func RectangularByFreq(fGHz float64) (*Rectangular, bool) {
+	for i := range RectangularBands {
+		b := &RectangularBands[i]
+		if fGHz >= b.FminGHz && fGHz <= b.FmaxGHz {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// CircularByFreq returns the circular band whose recommended range
+// contains fGHz, if any.
+func CircularByFreq(fGHz float64) (*Circular, bool) {
+	for i := range CircularBands {
+		b := &CircularBands[i]
+		if fGHz >= b.FminGHz && fGHz <= b.FmaxGHz {
+			return b, true
+		}
+	}
+	return nil, false
+}